@@ -22,6 +22,9 @@ type BasicLinker interface {
 	// Unlink removes the component/tag from the entity. Returns false if not linked.
 	Unlink(entity entity.Id) bool
 
+	// TryUnlink removes the component/tag from the entity, or returns a *LinkError describing why it can't.
+	TryUnlink(entity entity.Id) error
+
 	// ComponentId returns the unique identifier for this component type.
 	ComponentId() Id
 }
@@ -29,9 +32,14 @@ type BasicLinker interface {
 // Linker manages component instances of type T for entities.
 type Linker[T Component] interface {
 	// Link attaches a component to the entity and returns a pointer to it.
-	// Returns nil if already linked.
+	// Returns nil if already linked or the entity doesn't exist. In strict mode, it also rejects entities scheduled for
+	// removal, like TryLink, and panics with the *LinkError.
 	Link(entity entity.Id) *T
 
+	// TryLink attaches a component to the entity and returns a pointer to it, or a *LinkError describing why it can't
+	// (ErrEntityNotLinked, ErrAlreadyLinked or ErrStaleEntity).
+	TryLink(entity entity.Id) (*T, error)
+
 	// Get returns the component for the entity, or nil if not linked.
 	Get(entity entity.Id) *T
 
@@ -41,6 +49,13 @@ type Linker[T Component] interface {
 	// Unlink removes the component from the entity. Returns false if not linked.
 	Unlink(entity entity.Id) bool
 
+	// TryUnlink removes the component from the entity, or returns a *LinkError describing why it can't
+	// (ErrEntityNotLinked, ErrNotLinked or ErrAlreadyUnlinked).
+	TryUnlink(entity entity.Id) error
+
+	// MarkChanged records that the component was modified (used for change tracking). Returns false if not linked.
+	MarkChanged(entity entity.Id) bool
+
 	// SetLinkHook sets a callback invoked when a component is linked.
 	SetLinkHook(onLink func(*T))
 
@@ -62,15 +77,23 @@ type Linker[T Component] interface {
 
 // TagLinker manages tag associations for entities (no data storage).
 type TagLinker interface {
-	// Link attaches the tag to the entity. Returns false if already linked.
+	// Link attaches the tag to the entity.
+	// Returns false if already linked or the entity doesn't exist. In strict mode, it also rejects entities scheduled for
+	// removal, like TryLink, and panics with the *LinkError.
 	Link(entity entity.Id) bool
 
+	// TryLink attaches the tag to the entity, or returns a *LinkError describing why it can't.
+	TryLink(entity entity.Id) error
+
 	// Has returns true if the entity has this tag.
 	Has(entity entity.Id) bool
 
 	// Unlink removes the tag from the entity. Returns false if not linked.
 	Unlink(entity entity.Id) bool
 
+	// TryUnlink removes the tag from the entity, or returns a *LinkError describing why it can't.
+	TryUnlink(entity entity.Id) error
+
 	// SetLinkHook sets a callback invoked when a tag is linked.
 	SetLinkHook(onLink func())
 
@@ -89,3 +112,34 @@ type TagLinker interface {
 	// ComponentId returns the unique identifier for this tag.
 	ComponentId() Id
 }
+
+// Cloner is implemented by components that need a deep copy when copied into another entity
+// (e.g. components holding slices or maps). Without it, components are copied shallowly.
+type Cloner[T Component] interface {
+	// Clone returns a deep copy of the component.
+	Clone() T
+}
+
+// Ref is a reference to another entity, stored in component fields.
+// References are rewritten when entities are imported with new IDs. The zero value is an empty reference.
+type Ref struct {
+	Id    entity.Id
+	Valid bool
+}
+
+// RefTo returns a reference to the entity.
+func RefTo(entityId entity.Id) Ref {
+	return Ref{Id: entityId, Valid: true}
+}
+
+// Get returns the referenced entity, or false if the reference is empty.
+func (r Ref) Get() (entity.Id, bool) {
+	return r.Id, r.Valid
+}
+
+// Remapper is implemented by components storing entity IDs outside of exported Ref fields (e.g. relationship targets).
+// When such a component is imported, RemapEntities is called instead of rewriting its Ref fields.
+type Remapper interface {
+	// RemapEntities rewrites the stored entity IDs. The remap function returns false for entities outside the import.
+	RemapEntities(remap func(entity.Id) (entity.Id, bool))
+}
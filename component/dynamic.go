@@ -0,0 +1,445 @@
+package component
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/andrei-cosmin/sandecs/entity"
+)
+
+// Errors reported by dynamic components.
+var (
+	ErrInvalidSchema  = errors.New("component: invalid schema")
+	ErrSchemaConflict = errors.New("component: name registered with a different schema")
+	ErrUnknownField   = errors.New("component: unknown field")
+	ErrFieldKind      = errors.New("component: value doesn't match the field kind")
+)
+
+// FieldKind is the kind of a dynamic component field.
+type FieldKind uint8
+
+// Field kinds.
+const (
+	IntField    FieldKind = iota // int64
+	FloatField                   // float64
+	StringField                  // string
+	BoolField                    // bool
+	RefField                     // Ref
+	fieldKinds
+)
+
+// String returns the name of the field kind.
+func (k FieldKind) String() string {
+	switch k {
+	case IntField:
+		return "int"
+	case FloatField:
+		return "float"
+	case StringField:
+		return "string"
+	case BoolField:
+		return "bool"
+	case RefField:
+		return "ref"
+	default:
+		return fmt.Sprintf("FieldKind(%d)", uint8(k))
+	}
+}
+
+// Field describes a field of a dynamic component.
+type Field struct {
+	Name string
+	Kind FieldKind
+}
+
+// slot locates the value of a field within a record (the index among the values of its kind).
+type slot struct {
+	kind  FieldKind
+	index int
+}
+
+// Schema describes the fields of a dynamic component (a component defined at runtime, e.g. from data files).
+type Schema struct {
+	name   string
+	fields []Field
+	slots  map[string]slot
+	counts [fieldKinds]int
+}
+
+// NewSchema creates the schema of a dynamic component, registered under the given name.
+// Returns ErrInvalidSchema if the name is empty, or if a field name is empty, duplicated or has an unknown kind.
+func NewSchema(name string, fields ...Field) (*Schema, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: empty name", ErrInvalidSchema)
+	}
+	schema := &Schema{name: name, fields: slices.Clone(fields), slots: make(map[string]slot, len(fields))}
+	for _, field := range fields {
+		if field.Name == "" || field.Kind >= fieldKinds {
+			return nil, fmt.Errorf("%w: %s field %q of kind %s", ErrInvalidSchema, name, field.Name, field.Kind)
+		}
+		if _, ok := schema.slots[field.Name]; ok {
+			return nil, fmt.Errorf("%w: %s field %q is duplicated", ErrInvalidSchema, name, field.Name)
+		}
+		schema.slots[field.Name] = slot{kind: field.Kind, index: schema.counts[field.Kind]}
+		schema.counts[field.Kind]++
+	}
+	return schema, nil
+}
+
+// Name returns the name of the component.
+func (s *Schema) Name() string {
+	return s.name
+}
+
+// Fields returns the fields of the component, in declaration order.
+func (s *Schema) Fields() []Field {
+	return slices.Clone(s.fields)
+}
+
+// Equal returns true if both schemas have the same name and fields.
+func (s *Schema) Equal(other *Schema) bool {
+	return s.name == other.name && slices.Equal(s.fields, other.fields)
+}
+
+// DynamicLinker manages the records of a dynamic component.
+type DynamicLinker interface {
+	Linker[Record]
+
+	// Schema returns the schema of the component.
+	Schema() *Schema
+}
+
+// Record holds the field values of a dynamic component. Records are linked with their schema's zero values.
+//
+// The typed accessors (Int, SetInt, ...) panic on unknown fields or kind mismatches; Get and Set report them as errors.
+type Record struct {
+	schema  *Schema
+	ints    []int64
+	floats  []float64
+	strings []string
+	bools   []bool
+	refs    []Ref
+}
+
+// recordData is the encoded layout of a record (gob).
+type recordData struct {
+	Ints    []int64
+	Floats  []float64
+	Strings []string
+	Bools   []bool
+	Refs    []Ref
+}
+
+// Reset sets the record to the zero values of the schema.
+func (r *Record) Reset(schema *Schema) {
+	r.schema = schema
+	r.ints = resize(r.ints, schema.counts[IntField])
+	r.floats = resize(r.floats, schema.counts[FloatField])
+	r.strings = resize(r.strings, schema.counts[StringField])
+	r.bools = resize(r.bools, schema.counts[BoolField])
+	r.refs = resize(r.refs, schema.counts[RefField])
+}
+
+// Schema returns the schema of the record.
+func (r *Record) Schema() *Schema {
+	return r.schema
+}
+
+// Int returns the value of an int field.
+func (r *Record) Int(field string) int64 {
+	return r.ints[r.mustSlot(field, IntField)]
+}
+
+// SetInt sets the value of an int field.
+func (r *Record) SetInt(field string, value int64) {
+	r.ints[r.mustSlot(field, IntField)] = value
+}
+
+// Float returns the value of a float field.
+func (r *Record) Float(field string) float64 {
+	return r.floats[r.mustSlot(field, FloatField)]
+}
+
+// SetFloat sets the value of a float field.
+func (r *Record) SetFloat(field string, value float64) {
+	r.floats[r.mustSlot(field, FloatField)] = value
+}
+
+// String returns the value of a string field.
+func (r *Record) String(field string) string {
+	return r.strings[r.mustSlot(field, StringField)]
+}
+
+// SetString sets the value of a string field.
+func (r *Record) SetString(field string, value string) {
+	r.strings[r.mustSlot(field, StringField)] = value
+}
+
+// Bool returns the value of a bool field.
+func (r *Record) Bool(field string) bool {
+	return r.bools[r.mustSlot(field, BoolField)]
+}
+
+// SetBool sets the value of a bool field.
+func (r *Record) SetBool(field string, value bool) {
+	r.bools[r.mustSlot(field, BoolField)] = value
+}
+
+// Ref returns the value of an entity reference field.
+func (r *Record) Ref(field string) Ref {
+	return r.refs[r.mustSlot(field, RefField)]
+}
+
+// SetRef sets the value of an entity reference field.
+func (r *Record) SetRef(field string, value Ref) {
+	r.refs[r.mustSlot(field, RefField)] = value
+}
+
+// Get returns the value of a field (int64, float64, string, bool or Ref), or ErrUnknownField.
+func (r *Record) Get(field string) (any, error) {
+	location, ok := r.schema.slots[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s.%s", ErrUnknownField, r.schema.name, field)
+	}
+	switch location.kind {
+	case IntField:
+		return r.ints[location.index], nil
+	case FloatField:
+		return r.floats[location.index], nil
+	case StringField:
+		return r.strings[location.index], nil
+	case BoolField:
+		return r.bools[location.index], nil
+	default:
+		return r.refs[location.index], nil
+	}
+}
+
+// Set sets the value of a field, or returns ErrUnknownField or ErrFieldKind.
+// Int fields accept any Go integer, float fields accept floats and integers.
+func (r *Record) Set(field string, value any) error {
+	location, ok := r.schema.slots[field]
+	if !ok {
+		return fmt.Errorf("%w: %s.%s", ErrUnknownField, r.schema.name, field)
+	}
+
+	// Convert the value first, so that the field keeps its value if the conversion fails
+	converted := false
+	switch location.kind {
+	case IntField:
+		var integer int64
+		if integer, converted = toInt(value); converted {
+			r.ints[location.index] = integer
+		}
+	case FloatField:
+		var float float64
+		if float, converted = toFloat(value); converted {
+			r.floats[location.index] = float
+		}
+	case StringField:
+		var text string
+		if text, converted = value.(string); converted {
+			r.strings[location.index] = text
+		}
+	case BoolField:
+		var boolean bool
+		if boolean, converted = value.(bool); converted {
+			r.bools[location.index] = boolean
+		}
+	case RefField:
+		var ref Ref
+		if ref, converted = value.(Ref); converted {
+			r.refs[location.index] = ref
+		}
+	}
+	if !converted {
+		return fmt.Errorf("%w: %s.%s is %s, got %T", ErrFieldKind, r.schema.name, field, location.kind, value)
+	}
+	return nil
+}
+
+// Map returns the field values by name.
+func (r *Record) Map() map[string]any {
+	values := make(map[string]any, len(r.schema.fields))
+	for _, field := range r.schema.fields {
+		values[field.Name], _ = r.Get(field.Name)
+	}
+	return values
+}
+
+// Clone returns a deep copy of the record (see Cloner).
+func (r *Record) Clone() Record {
+	return Record{
+		schema:  r.schema,
+		ints:    slices.Clone(r.ints),
+		floats:  slices.Clone(r.floats),
+		strings: slices.Clone(r.strings),
+		bools:   slices.Clone(r.bools),
+		refs:    slices.Clone(r.refs),
+	}
+}
+
+// RemapEntities rewrites the entity references of the record (see Remapper).
+func (r *Record) RemapEntities(remap func(entity.Id) (entity.Id, bool)) {
+	for index := range r.refs {
+		if r.refs[index].Valid {
+			r.refs[index].Id, r.refs[index].Valid = remap(r.refs[index].Id)
+		}
+	}
+}
+
+// MarshalJSON encodes the record as an object of field values.
+func (r *Record) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for index, field := range r.schema.fields {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+		name, _ := json.Marshal(field.Name)
+		value, _ := r.Get(field.Name)
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(encoded)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON decodes an object of field values into the record (the record must have a schema, missing fields are zeroed).
+func (r *Record) UnmarshalJSON(data []byte) error {
+	if r.schema == nil {
+		return fmt.Errorf("%w: decoding a record without schema", ErrInvalidSchema)
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	r.Reset(r.schema)
+	for field, encoded := range values {
+		location, ok := r.schema.slots[field]
+		if !ok {
+			return fmt.Errorf("%w: %s.%s", ErrUnknownField, r.schema.name, field)
+		}
+		var err error
+		switch location.kind {
+		case IntField:
+			err = json.Unmarshal(encoded, &r.ints[location.index])
+		case FloatField:
+			err = json.Unmarshal(encoded, &r.floats[location.index])
+		case StringField:
+			err = json.Unmarshal(encoded, &r.strings[location.index])
+		case BoolField:
+			err = json.Unmarshal(encoded, &r.bools[location.index])
+		case RefField:
+			err = json.Unmarshal(encoded, &r.refs[location.index])
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s.%s: %w", ErrFieldKind, r.schema.name, field, err)
+		}
+	}
+	return nil
+}
+
+// GobEncode encodes the field values of the record.
+func (r *Record) GobEncode() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(recordData{Ints: r.ints, Floats: r.floats, Strings: r.strings, Bools: r.bools, Refs: r.refs})
+	return buffer.Bytes(), err
+}
+
+// GobDecode decodes the field values into the record (the record must have a schema with the same number of fields of each kind).
+func (r *Record) GobDecode(encoded []byte) error {
+	if r.schema == nil {
+		return fmt.Errorf("%w: decoding a record without schema", ErrInvalidSchema)
+	}
+	var data recordData
+	if err := gob.NewDecoder(bytes.NewReader(encoded)).Decode(&data); err != nil {
+		return err
+	}
+
+	r.Reset(r.schema)
+	if len(data.Ints) != len(r.ints) || len(data.Floats) != len(r.floats) || len(data.Strings) != len(r.strings) ||
+		len(data.Bools) != len(r.bools) || len(data.Refs) != len(r.refs) {
+		return fmt.Errorf("%w: encoded %s record doesn't match the schema", ErrInvalidSchema, r.schema.name)
+	}
+	copy(r.ints, data.Ints)
+	copy(r.floats, data.Floats)
+	copy(r.strings, data.Strings)
+	copy(r.bools, data.Bools)
+	copy(r.refs, data.Refs)
+	return nil
+}
+
+// mustSlot returns the index of the field among the values of its kind, panicking on unknown fields or kind mismatches.
+func (r *Record) mustSlot(field string, kind FieldKind) int {
+	location, ok := r.schema.slots[field]
+	if !ok {
+		panic(fmt.Errorf("%w: %s.%s", ErrUnknownField, r.schema.name, field))
+	}
+	if location.kind != kind {
+		panic(fmt.Errorf("%w: %s.%s is %s, not %s", ErrFieldKind, r.schema.name, field, location.kind, kind))
+	}
+	return location.index
+}
+
+// resize returns a zeroed slice of the given length, reusing the slice capacity.
+func resize[T any](values []T, length int) []T {
+	if cap(values) < length {
+		return make([]T, length)
+	}
+	values = values[:length]
+	clear(values)
+	return values
+}
+
+// toInt converts any Go integer to int64 (failing for unsigned values above math.MaxInt64).
+func toInt(value any) (int64, bool) {
+	switch integer := value.(type) {
+	case int:
+		return int64(integer), true
+	case int8:
+		return int64(integer), true
+	case int16:
+		return int64(integer), true
+	case int32:
+		return int64(integer), true
+	case int64:
+		return integer, true
+	case uint:
+		return int64(integer), uint64(integer) <= math.MaxInt64
+	case uint8:
+		return int64(integer), true
+	case uint16:
+		return int64(integer), true
+	case uint32:
+		return int64(integer), true
+	case uint64:
+		return int64(integer), integer <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+// toFloat converts a Go float or integer to a float64.
+func toFloat(value any) (float64, bool) {
+	switch float := value.(type) {
+	case float64:
+		return float, true
+	case float32:
+		return float64(float), true
+	default:
+		integer, ok := toInt(value)
+		return float64(integer), ok
+	}
+}
@@ -0,0 +1,47 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/andrei-cosmin/sandecs/entity"
+)
+
+// Errors reported by linker operations (see LinkError).
+var (
+	ErrEntityNotLinked = errors.New("component: entity not linked")
+	ErrAlreadyLinked   = errors.New("component: already linked")
+	ErrNotLinked       = errors.New("component: not linked")
+	ErrAlreadyUnlinked = errors.New("component: already scheduled for unlinking")
+	ErrStaleEntity     = errors.New("component: entity scheduled for removal")
+)
+
+// ErrUnknownComponent is reported when a filter or a name-based access references a component that isn't registered.
+var ErrUnknownComponent = errors.New("component: unknown component")
+
+// ErrNameConflict is reported when a component name is already used by another component.
+var ErrNameConflict = errors.New("component: name already registered")
+
+// ErrTagType is reported when a typed tag is registered for a type that isn't a zero-sized struct.
+var ErrTagType = errors.New("component: typed tags must be zero-sized structs")
+
+// ErrDuplicateKey is reported by unique-key indexes when several entities claim the same key.
+var ErrDuplicateKey = errors.New("component: duplicate key")
+
+// LinkError describes a failed linker operation. In strict mode, failed operations panic with a *LinkError.
+type LinkError struct {
+	Op        string    // Operation (link, unlink, get, mark changed)
+	Component string    // Component type name or tag
+	Entity    entity.Id // Entity ID
+	Err       error     // Reason (one of the Err* values)
+}
+
+// Error returns the error message.
+func (e *LinkError) Error() string {
+	return fmt.Sprintf("%s %s (entity %d): %v", e.Op, e.Component, e.Entity, e.Err)
+}
+
+// Unwrap returns the reason (for errors.Is).
+func (e *LinkError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,169 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/inspect"
+)
+
+// StatePath is the handler path serving the state as JSON (other paths serve the HTML page).
+const StatePath = "/state.json"
+
+// DefaultMaxWait is the default time a request waits for the next Update to render the entities.
+const DefaultMaxWait = time.Second
+
+// State is the sandbox state captured at an Update boundary.
+type State struct {
+	Stats         inspect.Stats       `json:"stats"`
+	CapturedAt    time.Time           `json:"capturedAt"`
+	Components    []inspect.Component `json:"components"`
+	Filters       []inspect.Filter    `json:"filters"`
+	Entities      []Entity            `json:"entities"`
+	EntitiesFrame uint64              `json:"entitiesFrame"` // Frame the entities were rendered at
+}
+
+// Entity holds the components (rendered values, by type name) and tags of an entity.
+type Entity struct {
+	Id         entity.Id      `json:"id"`
+	Components map[string]any `json:"components"`
+	Tags       []string       `json:"tags"`
+}
+
+// Handler serves the sandbox state, captured at the end of each Update.
+// Captures run on the goroutine calling Update; requests only read the latest published state.
+//
+// Rendering the component values of every entity is costly, so it is only done at the end of the Update following a request
+// (the request waits for it, up to MaxWait). The frame stats, components and filters are captured each frame.
+type Handler struct {
+	// MaxWait is the time a request waits for the next Update to render the entities (DefaultMaxWait by default).
+	// If the sandbox doesn't update in time, the request is served the entities rendered at State.EntitiesFrame.
+	// Set it before serving requests.
+	MaxWait time.Duration
+
+	sandbox   *sandbox.Sandbox
+	state     atomic.Pointer[State]
+	requested atomic.Bool
+	mutex     sync.Mutex
+	rendered  chan struct{} // Closed when the entities are rendered
+}
+
+// NewHandler creates a debug handler and captures the current state.
+// Call it from the goroutine that runs Update (the sandbox is not safe for concurrent use).
+func NewHandler(s *sandbox.Sandbox) *Handler {
+	handler := &Handler{MaxWait: DefaultMaxWait, sandbox: s, rendered: make(chan struct{})}
+	handler.capture(true)
+	sandbox.OnUpdate(s, func() {
+		handler.capture(handler.requested.Swap(false))
+	})
+	return handler
+}
+
+// State returns the latest captured state (without requesting the entities to be rendered).
+func (h *Handler) State() *State {
+	return h.state.Load()
+}
+
+// ServeHTTP serves the state as JSON on StatePath, and as a HTML page otherwise.
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		writer.Header().Set("Allow", "GET, HEAD")
+		http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	state := h.await(request.Context())
+	if request.URL.Path == StatePath {
+		writer.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(writer).Encode(state); err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(writer, pageData{State: state, StateURL: stateURL(request)}); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// pageData is the data of the HTML page: the state, and the absolute URL of its JSON version.
+type pageData struct {
+	*State
+	StateURL string
+}
+
+// stateURL returns the absolute URL path of the JSON state, from the path the handler is mounted at
+// (the request path before http.StripPrefix, minus the path seen by the handler).
+// A relative link would break when the page is served without a trailing slash (e.g. "/debug").
+func stateURL(request *http.Request) string {
+	requestPath := request.URL.Path
+	if requestURL, err := url.ParseRequestURI(request.RequestURI); err == nil {
+		requestPath = requestURL.Path
+	}
+	return strings.TrimSuffix(requestPath, request.URL.Path) + StatePath
+}
+
+// await requests the entities to be rendered, and returns the state once they are (or once MaxWait elapsed, or the request is canceled).
+func (h *Handler) await(ctx context.Context) *State {
+	h.mutex.Lock()
+	rendered := h.rendered
+	h.requested.Store(true)
+	h.mutex.Unlock()
+
+	if h.MaxWait > 0 {
+		timer := time.NewTimer(h.MaxWait)
+		defer timer.Stop()
+		select {
+		case <-rendered:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+	return h.state.Load()
+}
+
+// capture publishes the sandbox state. The entities are only rendered if requested, otherwise the previous ones are kept.
+func (h *Handler) capture(renderEntities bool) {
+	state := &State{
+		Stats:      sandbox.Stats(h.sandbox),
+		CapturedAt: time.Now(),
+		Components: sandbox.Components(h.sandbox),
+		Filters:    sandbox.Filters(h.sandbox),
+	}
+	if !renderEntities {
+		previous := h.state.Load()
+		state.Entities, state.EntitiesFrame = previous.Entities, previous.EntitiesFrame
+		h.state.Store(state)
+		return
+	}
+
+	entityIds := sandbox.Entities(h.sandbox)
+	state.Entities = make([]Entity, 0, len(entityIds))
+	state.EntitiesFrame = state.Stats.Frame
+	for _, entityId := range entityIds {
+		entry := Entity{Id: entityId, Components: map[string]any{}, Tags: []string{}}
+		for _, descriptor := range sandbox.EntityComponents(h.sandbox, entityId) {
+			if descriptor.Kind == inspect.TagKind {
+				entry.Tags = append(entry.Tags, descriptor.Name)
+				continue
+			}
+			entry.Components[descriptor.Name] = render(sandbox.ComponentValue(h.sandbox, entityId, descriptor.Id))
+		}
+		state.Entities = append(state.Entities, entry)
+	}
+	h.state.Store(state)
+
+	h.mutex.Lock()
+	close(h.rendered)
+	h.rendered = make(chan struct{})
+	h.mutex.Unlock()
+}
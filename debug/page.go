@@ -0,0 +1,47 @@
+package debug
+
+import (
+	"encoding/json"
+	"html/template"
+	"strings"
+)
+
+// page is the HTML view of the captured state.
+var page = template.Must(template.New("debug").Funcs(template.FuncMap{
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>sandecs debug</title>
+<style>
+body { font-family: monospace; margin: 1em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>Frame {{.Stats.Frame}}</h1>
+<p>{{.Stats.EntitiesLinked}} entities linked, {{.Stats.EntitiesUnlinked}} unlinked &middot; <a href="{{.StateURL}}">JSON</a></p>
+<h2>Components</h2>
+<table>
+<tr><th>ID</th><th>Name</th><th>Kind</th><th>Mode</th><th>Linked</th></tr>
+{{range .Components}}<tr><td>{{.Id}}</td><td>{{.Name}}</td><td>{{.Kind}}</td><td>{{if eq .Kind.String "component"}}{{.Mode}}{{end}}</td><td>{{.Linked}}</td></tr>
+{{end}}</table>
+<h2>Filters</h2>
+<table>
+<tr><th>Rules</th><th>Entities</th></tr>
+{{range .Filters}}<tr><td>{{.Name}}</td><td>{{.Entities}}</td></tr>
+{{end}}</table>
+<h2>Entities ({{len .Entities}}, frame {{.EntitiesFrame}})</h2>
+<table>
+<tr><th>ID</th><th>Components</th><th>Tags</th></tr>
+{{range .Entities}}<tr><td>{{.Id}}</td><td>{{json .Components}}</td><td>{{join .Tags ", "}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
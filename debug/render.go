@@ -0,0 +1,83 @@
+package debug
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/andrei-cosmin/sandecs/component"
+)
+
+// maxDepth limits the rendering of nested values (guards against cyclic pointers).
+const maxDepth = 8
+
+// render converts a value into JSON-friendly data (maps, slices and scalars), including unexported fields.
+func render(value any) any {
+	return renderValue(reflect.ValueOf(value), 0)
+}
+
+// renderValue renders a reflected value, up to maxDepth levels deep.
+func renderValue(value reflect.Value, depth int) any {
+	if !value.IsValid() {
+		return nil
+	}
+	if depth > maxDepth {
+		return "..."
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		// Dynamic component records are rendered by field name
+		if value.CanInterface() {
+			if record, ok := value.Interface().(*component.Record); ok {
+				return renderValue(reflect.ValueOf(record.Map()), depth+1)
+			}
+		}
+		return renderValue(value.Elem(), depth+1)
+	case reflect.Struct:
+		fields := make(map[string]any, value.NumField())
+		for index := range value.NumField() {
+			fields[value.Type().Field(index).Name] = renderValue(value.Field(index), depth+1)
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		items := make([]any, value.Len())
+		for index := range value.Len() {
+			items[index] = renderValue(value.Index(index), depth+1)
+		}
+		return items
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		entries := make(map[string]any, value.Len())
+		iterator := value.MapRange()
+		for iterator.Next() {
+			entries[fmt.Sprint(iterator.Key())] = renderValue(iterator.Value(), depth+1)
+		}
+		return entries
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint()
+	case reflect.Float32, reflect.Float64:
+		// JSON has no representation for NaN and infinities
+		if float := value.Float(); !math.IsNaN(float) && !math.IsInf(float, 0) {
+			return float
+		}
+		return fmt.Sprint(value)
+	case reflect.String:
+		return value.String()
+	default:
+		// Complex numbers, channels, functions and unsafe pointers are rendered by type
+		return value.Type().String()
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"iter"
+
+	"github.com/andrei-cosmin/sandata/bit"
+)
+
+// Set is a short-lived set of entities, resulting from set operations on views (see filter.View).
+// Its buffer is pooled: release the set once done, and don't use it afterward.
+type Set interface {
+	MaskView
+
+	// And keeps the entities present in the mask (in place), and returns the set.
+	And(mask bit.Mask) Set
+
+	// AndNot drops the entities present in the mask (in place), and returns the set.
+	AndNot(mask bit.Mask) Set
+
+	// Count returns the number of entities.
+	Count() uint
+
+	// Contains returns true if the entity is in the set.
+	Contains(entityId Id) bool
+
+	// First returns the lowest entity ID, or false if the set is empty.
+	First() (Id, bool)
+
+	// Any returns true if the set isn't empty.
+	Any() bool
+
+	// All iterates over the entities, in ascending order. The iterator is reused by the set, so iterating doesn't allocate.
+	All() iter.Seq[Id]
+
+	// Release returns the set buffer to the pool.
+	Release()
+}
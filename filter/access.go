@@ -0,0 +1,22 @@
+package filter
+
+import "github.com/andrei-cosmin/sandecs/component"
+
+// Access is the access of a typed query to a component (see Read and Write).
+// Components without a declared access are reported as written.
+type Access struct {
+	ComponentId component.Id
+	Write       bool
+}
+
+// Conflicts returns true if two access sets can't run in parallel (a component written by one and accessed by the other).
+func Conflicts(first, second []Access) bool {
+	for _, current := range first {
+		for _, other := range second {
+			if current.ComponentId == other.ComponentId && (current.Write || other.Write) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/internal/sandbox"
+)
+
+// QueryOption is an option of typed queries (see sandbox.NewQuery2): a Filter, or a Declaration.
+type QueryOption interface {
+	queryOption()
+}
+
+// Declaration declares how typed queries access a component (see Optional, Read and Write).
+// Declarations don't filter entities, so only typed queries accept them.
+type Declaration struct {
+	Declarations []sandbox.Declaration
+}
+
+func (Filter) queryOption() {}
+
+func (Declaration) queryOption() {}
+
+// Optional marks component T as optional in typed queries (see sandbox.NewQuery2): entities without it are still matched,
+// and the query yields nil for it. It has no effect on the entities matched by other filters.
+func Optional[T component.Component]() Declaration {
+	return Declaration{
+		Declarations: []sandbox.Declaration{
+			sandbox.NewComponentDeclaration[T](sandbox.Optional),
+		},
+	}
+}
+
+// Read declares read-only access of typed queries to component T: the query yields a pointer to a copy of the component,
+// so modifications don't reach the stored component.
+func Read[T component.Component]() Declaration {
+	return Declaration{
+		Declarations: []sandbox.Declaration{
+			sandbox.NewComponentDeclaration[T](sandbox.Read),
+		},
+	}
+}
+
+// Write declares mutable access of typed queries to component T: the component is marked changed after each call of the query function.
+func Write[T component.Component]() Declaration {
+	return Declaration{
+		Declarations: []sandbox.Declaration{
+			sandbox.NewComponentDeclaration[T](sandbox.Write),
+		},
+	}
+}
@@ -0,0 +1,11 @@
+package filter
+
+import "errors"
+
+// ErrFilterAfterStart is reported in strict mode when a filter is created after the first Update
+// (filters should be registered during initialization).
+var ErrFilterAfterStart = errors.New("filter: created after the first update")
+
+// ErrEmptyQuery is the panic value of typed queries without match, exclude or union rules
+// (e.g. all components optional and no other filters), which would never match any entity.
+var ErrEmptyQuery = errors.New("filter: query without match, exclude or union rules")
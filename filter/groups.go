@@ -0,0 +1,24 @@
+package filter
+
+import (
+	"iter"
+
+	"github.com/andrei-cosmin/sandecs/entity"
+)
+
+// Groups partitions the entities of a view by a key computed from one of their components.
+type Groups[K comparable] interface {
+	// Group returns a view of the entities with the given key (empty if there are none).
+	// The returned view stays up to date with subsequent updates.
+	Group(key K) entity.View
+
+	// Keys returns the keys having at least one entity, in the order they appeared
+	// (groups are removed once empty, so a key appearing again moves to the end).
+	Keys() []K
+
+	// All iterates over the keys having at least one entity and their groups (views as returned by Group), in the order they appeared.
+	All() iter.Seq2[K, entity.View]
+
+	// Release stops tracking the component changes: the groups are no longer updated (calling Release again has no effect).
+	Release()
+}
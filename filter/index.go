@@ -0,0 +1,20 @@
+package filter
+
+import "github.com/andrei-cosmin/sandecs/entity"
+
+// Index maps unique keys, computed from a component, to entities.
+type Index[K comparable] interface {
+	// Lookup returns the entity owning the key, or false if no entity has it.
+	Lookup(key K) (entity.Id, bool)
+
+	// Conflicts returns an error describing the keys claimed by several entities (wrapping component.ErrDuplicateKey),
+	// or nil if all keys are unique. The keys are ordered by the entity owning them.
+	Conflicts() error
+
+	// OnConflict sets a callback invoked (on update) when an entity claims a key already owned by another entity.
+	// The entities linked before the index was created are indexed on first use, so their conflicts are reported too.
+	OnConflict(onConflict func(key K, owner, claimant entity.Id))
+
+	// Release stops updating the index. The index must not be used afterward (calling Release again has no effect).
+	Release()
+}
@@ -18,6 +18,30 @@ func createTagRules(ruleType sandbox.Type, tags ...component.Tag) Filter {
 	return Filter{Rules: rules}
 }
 
+func createIdRules(ruleType sandbox.Type, componentIds ...component.Id) Filter {
+	rules := make([]sandbox.Rule, len(componentIds))
+	for index, componentId := range componentIds {
+		rules[index] = sandbox.NewIdRule(componentId, ruleType)
+	}
+	return Filter{Rules: rules}
+}
+
+// MatchIds matches entities with all specified components, by ID (e.g. dynamic components, see component.DynamicLinker).
+// Filtering panics with component.ErrUnknownComponent if an ID isn't registered.
+func MatchIds(componentIds ...component.Id) Filter {
+	return createIdRules(sandbox.Match, componentIds...)
+}
+
+// ExcludeIds excludes entities with any of the specified components, by ID.
+func ExcludeIds(componentIds ...component.Id) Filter {
+	return createIdRules(sandbox.Exclude, componentIds...)
+}
+
+// UnionIds matches entities with at least one of the specified components, by ID.
+func UnionIds(componentIds ...component.Id) Filter {
+	return createIdRules(sandbox.Union, componentIds...)
+}
+
 // MatchTags matches entities with all specified tags.
 func MatchTags(tags ...component.Tag) Filter {
 	return createTagRules(sandbox.Match, tags...)
@@ -33,6 +57,33 @@ func UnionTags(tags ...component.Tag) Filter {
 	return createTagRules(sandbox.Union, tags...)
 }
 
+// MatchTag matches entities with the typed tag T (see sandbox.TypedTagLinker).
+func MatchTag[T component.Component]() Filter {
+	return Filter{
+		Rules: []sandbox.Rule{
+			sandbox.NewTypedTagRule[T](sandbox.Match),
+		},
+	}
+}
+
+// ExcludeTag excludes entities with the typed tag T.
+func ExcludeTag[T component.Component]() Filter {
+	return Filter{
+		Rules: []sandbox.Rule{
+			sandbox.NewTypedTagRule[T](sandbox.Exclude),
+		},
+	}
+}
+
+// UnionTag matches entities with the typed tag T (at least one of the union rules must match).
+func UnionTag[T component.Component]() Filter {
+	return Filter{
+		Rules: []sandbox.Rule{
+			sandbox.NewTypedTagRule[T](sandbox.Union),
+		},
+	}
+}
+
 // Match matches entities with component T.
 func Match[T component.Component]() Filter {
 	return Filter{
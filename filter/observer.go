@@ -0,0 +1,7 @@
+package filter
+
+// Observer reports the changes of a component to callbacks (see sandbox.Observe).
+type Observer interface {
+	// Release stops the observer: its callbacks are no longer invoked (calling Release again has no effect).
+	Release()
+}
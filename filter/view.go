@@ -0,0 +1,42 @@
+package filter
+
+import (
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/entity"
+)
+
+// View is a view of the entities matching a filter.
+type View interface {
+	entity.View
+
+	// And returns a set of the view entities present in the mask (e.g. a selection, or another view's EntityMask).
+	// The set is pooled: release it once done (it reflects the view at the time of the call).
+	And(mask bit.Mask) entity.Set
+
+	// AndNot returns a set of the view entities not present in the mask. The set is pooled: release it once done.
+	AndNot(mask bit.Mask) entity.Set
+
+	// Count returns the number of entities in the view.
+	Count() uint
+
+	// Contains returns true if the entity is in the view.
+	Contains(entityId entity.Id) bool
+
+	// First returns the lowest entity ID in the view, or false if the view is empty.
+	First() (entity.Id, bool)
+
+	// Any returns true if the view isn't empty.
+	Any() bool
+
+	// Release drops the view. Once all the views sharing the filter are released, the filter is unregistered
+	// and no longer updated. The view must not be used afterward (calling Release again has no effect).
+	Release()
+}
+
+// SortedView is a view of entities ordered by one of their components (see sandbox.SortedView).
+type SortedView interface {
+	entity.View
+
+	// Release stops tracking the component changes: the order is no longer updated (calling Release again has no effect).
+	Release()
+}
@@ -0,0 +1,6 @@
+package inspect
+
+import "errors"
+
+// ErrInconsistent is returned by validation when the sandbox internals don't satisfy their invariants.
+var ErrInconsistent = errors.New("inspect: inconsistent sandbox state")
@@ -0,0 +1,54 @@
+package inspect
+
+import (
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/options"
+)
+
+// Kind distinguishes components from tags.
+type Kind byte
+
+// Component kinds.
+const (
+	ComponentKind Kind = iota // Component with stored values
+	TagKind                   // Tag (no backing storage)
+)
+
+// String returns the kind name.
+func (k Kind) String() string {
+	switch k {
+	case ComponentKind:
+		return "component"
+	case TagKind:
+		return "tag"
+	default:
+		return "unknown"
+	}
+}
+
+// Component describes a registered component or tag.
+type Component struct {
+	Id     component.Id
+	Name   string
+	Kind   Kind
+	Mode   options.Mode      // Storage mode (components only)
+	Linked uint              // Number of linked entities
+	Fields []component.Field // Schema fields (dynamic components only)
+}
+
+// Filter describes a registered filter.
+type Filter struct {
+	Name     string   // Rules summary, e.g. "Match(Position, Velocity) Exclude(dead)"
+	Match    []string // Required component and tag names
+	Exclude  []string // Excluded component and tag names
+	Union    []string // Component and tag names where at least one must be present
+	Entities uint     // Number of matched entities
+	Views    uint     // Number of unreleased views sharing the filter
+}
+
+// View describes an unreleased filter view (views are only recorded with options.TrackViews).
+type View struct {
+	Filter string // Rules summary of the filter
+	Site   string // Creation site as file:line (options.TrackViews only)
+	Leaked bool   // Garbage collected without being released (options.TrackViews only)
+}
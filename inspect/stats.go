@@ -0,0 +1,51 @@
+package inspect
+
+import (
+	"time"
+
+	"github.com/andrei-cosmin/sandecs/component"
+)
+
+// Stats holds the statistics of a frame (the changes processed by an Update call).
+type Stats struct {
+	Frame             uint64           // Number of Update calls (starting at 1)
+	EntitiesLinked    uint             // Entities linked during the frame
+	EntitiesUnlinked  uint             // Entities unlinked during the frame
+	Components        []ComponentStats // Per component and tag, ordered by component ID
+	FiltersRecomputed uint             // Filter evaluations
+	FiltersChanged    uint             // Filter evaluations that changed the matched entities
+	Phases            Phases           // Time spent in each Update phase
+}
+
+// ComponentStats holds the frame statistics of a component or tag.
+type ComponentStats struct {
+	Id       component.Id
+	Name     string
+	Linked   uint
+	Unlinked uint
+}
+
+// Phases holds the time spent in each Update phase.
+type Phases struct {
+	Entities   time.Duration // Removing unlinked entities
+	Components time.Duration // Processing component links, unlinks and changes
+	Filters    time.Duration // Recomputing filters
+}
+
+// Total returns the time spent in all phases.
+func (p Phases) Total() time.Duration {
+	return p.Entities + p.Components + p.Filters
+}
+
+// Instrumentation receives the statistics at the end of each frame.
+type Instrumentation interface {
+	RecordFrame(stats Stats)
+}
+
+// InstrumentationFunc adapts a function to the Instrumentation interface.
+type InstrumentationFunc func(stats Stats)
+
+// RecordFrame calls the function.
+func (f InstrumentationFunc) RecordFrame(stats Stats) {
+	f(stats)
+}
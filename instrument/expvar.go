@@ -0,0 +1,56 @@
+package instrument
+
+import (
+	"expvar"
+	"sync/atomic"
+
+	"github.com/andrei-cosmin/sandecs/inspect"
+)
+
+// expvarInstrumentation publishes cumulative counters and the last frame statistics.
+type expvarInstrumentation struct {
+	frames            *expvar.Int
+	entitiesLinked    *expvar.Int
+	entitiesUnlinked  *expvar.Int
+	filtersRecomputed *expvar.Int
+	filtersChanged    *expvar.Int
+	updateNanos       *expvar.Int
+	lastFrame         atomic.Pointer[inspect.Stats]
+}
+
+// Expvar creates an instrumentation publishing a map with the given name, holding cumulative counters
+// (frames, entitiesLinked, entitiesUnlinked, filtersRecomputed, filtersChanged, updateNanos) and the lastFrame statistics.
+// Like expvar.Publish, it panics if the name is already in use.
+func Expvar(name string) inspect.Instrumentation {
+	instrumentation := &expvarInstrumentation{
+		frames:            new(expvar.Int),
+		entitiesLinked:    new(expvar.Int),
+		entitiesUnlinked:  new(expvar.Int),
+		filtersRecomputed: new(expvar.Int),
+		filtersChanged:    new(expvar.Int),
+		updateNanos:       new(expvar.Int),
+	}
+
+	vars := expvar.NewMap(name)
+	vars.Set("frames", instrumentation.frames)
+	vars.Set("entitiesLinked", instrumentation.entitiesLinked)
+	vars.Set("entitiesUnlinked", instrumentation.entitiesUnlinked)
+	vars.Set("filtersRecomputed", instrumentation.filtersRecomputed)
+	vars.Set("filtersChanged", instrumentation.filtersChanged)
+	vars.Set("updateNanos", instrumentation.updateNanos)
+	vars.Set("lastFrame", expvar.Func(func() any {
+		return instrumentation.lastFrame.Load()
+	}))
+	return instrumentation
+}
+
+// RecordFrame adds the frame statistics to the counters.
+func (i *expvarInstrumentation) RecordFrame(stats inspect.Stats) {
+	i.frames.Add(1)
+	i.entitiesLinked.Add(int64(stats.EntitiesLinked))
+	i.entitiesUnlinked.Add(int64(stats.EntitiesUnlinked))
+	i.filtersRecomputed.Add(int64(stats.FiltersRecomputed))
+	i.filtersChanged.Add(int64(stats.FiltersChanged))
+	i.updateNanos.Add(stats.Phases.Total().Nanoseconds())
+	i.lastFrame.Store(&stats)
+}
@@ -0,0 +1,58 @@
+package instrument
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/andrei-cosmin/sandecs/inspect"
+)
+
+// slogInstrumentation logs the statistics of each frame.
+type slogInstrumentation struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+// Slog creates an instrumentation logging one record per frame at the given level
+// (components and tags without links or unlinks are omitted).
+func Slog(logger *slog.Logger, level slog.Level) inspect.Instrumentation {
+	return &slogInstrumentation{logger: logger, level: level}
+}
+
+// RecordFrame logs the frame statistics.
+func (i *slogInstrumentation) RecordFrame(stats inspect.Stats) {
+	ctx := context.Background()
+	if !i.logger.Enabled(ctx, i.level) {
+		return
+	}
+
+	components := make([]any, 0, len(stats.Components))
+	for _, componentStats := range stats.Components {
+		if componentStats.Linked == 0 && componentStats.Unlinked == 0 {
+			continue
+		}
+		components = append(components, slog.Group(componentStats.Name,
+			slog.Uint64("linked", uint64(componentStats.Linked)),
+			slog.Uint64("unlinked", uint64(componentStats.Unlinked)),
+		))
+	}
+
+	i.logger.LogAttrs(ctx, i.level, "sandbox frame",
+		slog.Uint64("frame", stats.Frame),
+		slog.Group("entities",
+			slog.Uint64("linked", uint64(stats.EntitiesLinked)),
+			slog.Uint64("unlinked", uint64(stats.EntitiesUnlinked)),
+		),
+		slog.Group("components", components...),
+		slog.Group("filters",
+			slog.Uint64("recomputed", uint64(stats.FiltersRecomputed)),
+			slog.Uint64("changed", uint64(stats.FiltersChanged)),
+		),
+		slog.Group("phases",
+			slog.Duration("entities", stats.Phases.Entities),
+			slog.Duration("components", stats.Phases.Components),
+			slog.Duration("filters", stats.Phases.Filters),
+			slog.Duration("total", stats.Phases.Total()),
+		),
+	)
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"github.com/andrei-cosmin/sandata/bit"
 	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/options"
 )
 
 // ComponentLinkRetriever retrieves component linkers by ID.
@@ -13,7 +15,11 @@ type ComponentLinkRetriever interface {
 // ComponentLinkManager manages all component linkers.
 type ComponentLinkManager interface {
 	Get(componentId component.Id) ComponentLinker
-	UpdateLinks(scheduledSandboxRemoves bit.Mask)
+	LookupComponent(name string) (ComponentLinker, bool)
+	LookupTag(tag component.Tag) (ComponentLinker, bool)
+	Size() uint
+	Tick() uint64
+	UpdateLinks(scheduledSandboxRemoves bit.Mask) []uint
 	Accept(registration Registration)
 	IsCleared() bool
 }
@@ -21,12 +27,64 @@ type ComponentLinkManager interface {
 // ComponentLinker manages instances of a single component type.
 type ComponentLinker interface {
 	ComponentId() component.Id
+	ComponentType() string
 	EntityMask() bit.Mask
+	ChangedSince(entityId entity.Id, tick uint64) bool
+	MarkChanged(entityId entity.Id) bool
+	CommitChanges(tick uint64)
+
+	// Watch registers a watcher called during updates with the entities whose component was linked, marked changed or unlinked.
+	// Returns a function unregistering the watcher.
+	Watch(watcher func(entities bit.Mask)) (unwatch func())
+
+	TryUnlink(entityId entity.Id) error
+	Validate() error
 	CleanScheduledEntities(scheduledSandboxRemoves bit.Mask)
 	CleanScheduledInstances()
 	Refresh()
 }
 
+// InstanceLinker provides untyped access to the instances of a component linker (not implemented by tags).
+type InstanceLinker interface {
+	ComponentLinker
+
+	// Mode returns the storage mode of the instances.
+	Mode() options.Mode
+
+	// NewInstance returns a pointer to a new zero-valued instance.
+	NewInstance() any
+
+	// GetInstance returns a pointer to the entity's instance, or nil if not linked.
+	GetInstance(entityId entity.Id) any
+
+	// SetInstance links the component if needed and copies the given instance into it.
+	// Returns false if the entity doesn't exist.
+	SetInstance(entityId entity.Id, instance any) bool
+
+	// CloneInstance links the component to the target entity and copies the source entity's instance into it.
+	// Returns false if the source isn't linked or the target doesn't exist.
+	CloneInstance(sourceId, targetId entity.Id) bool
+
+	// SetInstances links the component to all the entities at once and copies the given instance into each of them.
+	SetInstances(entityIds []entity.Id, instance any)
+}
+
+// DynamicLinker provides the schema of a dynamic component linker (see component.DynamicLinker).
+type DynamicLinker interface {
+	InstanceLinker
+
+	// Schema returns the schema of the component.
+	Schema() *component.Schema
+}
+
+// BatchLinker links many entities at once (used for tags).
+type BatchLinker interface {
+	ComponentLinker
+
+	// LinkBatch links all the given entities, skipping the ones already linked or not existing.
+	LinkBatch(entityIds []entity.Id)
+}
+
 // Registration registers a component linker with the manager.
 type Registration interface {
 	Execute(ctx ComponentLinkManager)
@@ -10,6 +10,12 @@ type EntityLinker interface {
 	// Link creates a new entity and returns its ID.
 	Link() entity.Id
 
+	// LinkN creates count new entities and returns their IDs.
+	LinkN(count uint) []entity.Id
+
+	// LinkId links the given entity ID. Returns false if it is already linked.
+	LinkId(entityId entity.Id) bool
+
 	// Unlink schedules entity removal (along with all its components).
 	Unlink(entityId entity.Id)
 
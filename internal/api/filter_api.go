@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/andrei-cosmin/sandata/bit"
 	"github.com/andrei-cosmin/sandecs/component"
 	"github.com/andrei-cosmin/sandecs/entity"
 )
@@ -15,11 +16,54 @@ type FilterRules interface {
 	UnionComponentIds() []component.Id
 }
 
+// FilterCache is a registered filter view, along with its rules.
+type FilterCache interface {
+	FilterRules
+	entity.View
+
+	// References returns the number of unreleased views sharing the cache.
+	References() uint
+}
+
+// FilterView is a filter view that can be released once unused, along with set operations on its entities.
+type FilterView interface {
+	entity.View
+	And(mask bit.Mask) entity.Set
+	AndNot(mask bit.Mask) entity.Set
+	Count() uint
+	Contains(entityId entity.Id) bool
+	First() (entity.Id, bool)
+	Any() bool
+	Release()
+}
+
+// ViewInfo describes an unreleased filter view (only recorded if views are tracked).
+type ViewInfo struct {
+	Cache  FilterCache
+	Site   string // Creation site
+	Leaked bool   // Garbage collected without being released
+}
+
 // FilterRegistry manages filter registration and cached results.
 type FilterRegistry interface {
 	// Register creates a filter view from the given rules.
-	Register(filter FilterRules) entity.View
+	Register(filter FilterRules) FilterView
+
+	// UpdateLinks refreshes all filter caches, returning the number of caches whose entities changed.
+	UpdateLinks() uint
+
+	// Caches returns the registered filter caches.
+	Caches() []FilterCache
+
+	// Size returns the number of registered filter caches.
+	Size() uint
+
+	// Query computes the entities currently matching the rules, without registering a cache.
+	Query(filter FilterRules) []entity.Id
+
+	// Views returns the unreleased views (empty unless views are tracked).
+	Views() []ViewInfo
 
-	// UpdateLinks refreshes all filter caches.
-	UpdateLinks()
+	// Validate checks that every filter cache matches a fresh recomputation of its rules.
+	Validate() error
 }
@@ -1,9 +1,15 @@
 package component
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/andrei-cosmin/sandata/array"
 	"github.com/andrei-cosmin/sandata/bit"
 	"github.com/andrei-cosmin/sandecs/component"
 	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
 	"github.com/bits-and-blooms/bitset"
 )
 
@@ -11,31 +17,80 @@ import (
 type baseLinker struct {
 	componentId      component.Id
 	componentType    string
-	entityLinker     entity.MaskView
+	entityLinker     api.EntityLinker
+	strict           bool
 	callback         func()
 	scheduledRemoves *bit.BitMask
 	linkedEntities   *bit.BitMask
+	changedEntities  *bit.BitMask
+	changeTicks      array.Array[uint64]
+	watchers         []*watcher
+}
+
+// watcher is notified of the entities whose component changed during an update.
+type watcher struct {
+	notify func(entities bit.Mask)
 }
 
-func newBaseLinker(size uint, componentId component.Id, componentType string, entityLinker entity.MaskView, callback func()) *baseLinker {
+func newBaseLinker(size uint, componentId component.Id, componentType string, entityLinker api.EntityLinker, strict bool, callback func()) *baseLinker {
 	return &baseLinker{
 		componentId:      componentId,
 		componentType:    componentType,
 		entityLinker:     entityLinker,
+		strict:           strict,
 		callback:         callback,
 		scheduledRemoves: bit.NewMask(bitset.New(size)),
 		linkedEntities:   bit.NewMask(bitset.New(size)),
+		changedEntities:  bit.NewMask(bitset.New(size)),
+		changeTicks:      *array.New[uint64](size),
 	}
 }
 
-// Link associates the entity with this component. Returns false if already linked or entity doesn't exist.
+// Link associates the entity with this component. Returns false if it can't be linked (see link), panicking in strict mode.
+// Entities scheduled for removal are only rejected in strict mode, keeping the default path free of the extra check.
 func (r *baseLinker) Link(entityId entity.Id) bool {
-	if !r.entityLinker.EntityMask().Test(entityId) || r.Has(entityId) {
+	if err := r.link(entityId, r.strict); err != nil {
+		r.fail("link", entityId, err)
 		return false
 	}
+	return true
+}
+
+// link associates the entity with this component, returning the reason on failure.
+// Entities scheduled for removal are only rejected if checkStale is set.
+func (r *baseLinker) link(entityId entity.Id, checkStale bool) error {
+	if !r.entityLinker.EntityMask().Test(entityId) {
+		return component.ErrEntityNotLinked
+	}
+	if r.Has(entityId) {
+		return component.ErrAlreadyLinked
+	}
+	// The component would be dropped along with the entity on the next update
+	if checkStale && r.entityLinker.GetScheduledRemoves().Test(entityId) {
+		return component.ErrStaleEntity
+	}
 	r.linkedEntities.Bits().Set(entityId)
+	r.changedEntities.Bits().Set(entityId)
 	r.callback()
-	return true
+	return nil
+}
+
+// linkBatch associates all the entities with this component, triggering the callback once.
+// Returns the entities that were linked (skipping the ones already linked or not existing).
+func (r *baseLinker) linkBatch(entityIds []entity.Id) []entity.Id {
+	linked := make([]entity.Id, 0, len(entityIds))
+	for _, entityId := range entityIds {
+		if !r.entityLinker.EntityMask().Test(entityId) || r.Has(entityId) {
+			continue
+		}
+		r.linkedEntities.Bits().Set(entityId)
+		r.changedEntities.Bits().Set(entityId)
+		linked = append(linked, entityId)
+	}
+	if len(linked) > 0 {
+		r.callback()
+	}
+	return linked
 }
 
 // Has returns true if the entity has this component.
@@ -45,32 +100,140 @@ func (r *baseLinker) Has(entityId entity.Id) bool {
 
 // Unlink schedules removal of the component from the entity.
 func (r *baseLinker) Unlink(entityId entity.Id) bool {
-	if !r.entityLinker.EntityMask().Test(entityId) || !r.Has(entityId) {
+	if err := r.unlink(entityId); err != nil {
+		r.fail("unlink", entityId, err)
 		return false
 	}
+	return true
+}
+
+// TryUnlink schedules removal of the component from the entity, returning a *component.LinkError on failure.
+func (r *baseLinker) TryUnlink(entityId entity.Id) error {
+	if err := r.unlink(entityId); err != nil {
+		return r.linkError("unlink", entityId, err)
+	}
+	return nil
+}
+
+// unlink schedules removal of the component from the entity, returning the reason on failure.
+func (r *baseLinker) unlink(entityId entity.Id) error {
+	if err := r.checkLinked(entityId); err != nil {
+		return err
+	}
 	if r.scheduledRemoves.Test(entityId) {
-		return false
+		return component.ErrAlreadyUnlinked
 	}
 	r.scheduledRemoves.Bits().Set(entityId)
 	r.callback()
+	return nil
+}
+
+// checkLinked returns the reason why the entity doesn't have this component (nil if it does).
+func (r *baseLinker) checkLinked(entityId entity.Id) error {
+	if !r.entityLinker.EntityMask().Test(entityId) {
+		return component.ErrEntityNotLinked
+	}
+	if !r.Has(entityId) {
+		return component.ErrNotLinked
+	}
+	return nil
+}
+
+// fail reports a failed operation, panicking with a *component.LinkError in strict mode.
+func (r *baseLinker) fail(op string, entityId entity.Id, err error) {
+	if r.strict {
+		panic(r.linkError(op, entityId, err))
+	}
+}
+
+// linkError wraps the reason of a failed operation.
+func (r *baseLinker) linkError(op string, entityId entity.Id, err error) *component.LinkError {
+	return &component.LinkError{Op: op, Component: r.componentType, Entity: entityId, Err: err}
+}
+
+// MarkChanged marks the entity's component as changed. Returns false if not linked.
+func (r *baseLinker) MarkChanged(entityId entity.Id) bool {
+	if !r.Has(entityId) {
+		r.fail("mark changed", entityId, r.checkLinked(entityId))
+		return false
+	}
+	r.changedEntities.Bits().Set(entityId)
+	r.callback()
 	return true
 }
 
+// ChangedSince returns true if the entity's component was linked or marked changed after the given tick (or is pending).
+func (r *baseLinker) ChangedSince(entityId entity.Id, tick uint64) bool {
+	if r.changedEntities.Test(entityId) {
+		return true
+	}
+	return entityId < r.changeTicks.Size() && r.changeTicks.Get(entityId) > tick
+}
+
+// CommitChanges stamps the pending changes with the given tick, notifying the watchers.
+func (r *baseLinker) CommitChanges(tick uint64) {
+	if r.changedEntities.Any() {
+		r.notify(r.changedEntities)
+	}
+	for entityId, hasNext := r.changedEntities.NextSet(0); hasNext; entityId, hasNext = r.changedEntities.NextSet(entityId + 1) {
+		r.changeTicks.Set(entityId, tick)
+	}
+	r.changedEntities.Bits().ClearAll()
+}
+
+// Watch registers a watcher called with the changed entities (on commit) and the unlinked entities (on cleanup).
+func (r *baseLinker) Watch(notify func(entities bit.Mask)) func() {
+	entry := &watcher{notify: notify}
+	r.watchers = append(r.watchers, entry)
+	return func() {
+		r.watchers = slices.DeleteFunc(r.watchers, func(other *watcher) bool {
+			return other == entry
+		})
+	}
+}
+
+// notify calls the watchers with the given entities.
+func (r *baseLinker) notify(entities bit.Mask) {
+	for _, entry := range r.watchers {
+		entry.notify(entities)
+	}
+}
+
+// Validate checks that the linked entities exist and that the scheduled removals are linked.
+func (r *baseLinker) Validate() error {
+	var errs []error
+	if count := r.entityLinker.EntityMask().DifferenceCardinality(r.linkedEntities.Bits()); count > 0 {
+		errs = append(errs, fmt.Errorf("%d linked entities don't exist", count))
+	}
+	if count := r.linkedEntities.DifferenceCardinality(r.scheduledRemoves.Bits()); count > 0 {
+		errs = append(errs, fmt.Errorf("%d scheduled removals aren't linked", count))
+	}
+	return errors.Join(errs...)
+}
+
 // ComponentId returns the component ID.
 func (r *baseLinker) ComponentId() component.Id {
 	return r.componentId
 }
 
+// ComponentType returns the registered label of the component (type name or tag).
+func (r *baseLinker) ComponentType() string {
+	return r.componentType
+}
+
 // EntityMask returns the bitmask of linked entities.
 func (r *baseLinker) EntityMask() bit.Mask {
 	return r.linkedEntities
 }
 
-// CleanScheduledEntities removes scheduled entities from the linked set.
+// CleanScheduledEntities removes scheduled entities from the linked set, notifying the watchers of the unlinked entities.
 func (r *baseLinker) CleanScheduledEntities(scheduledSandboxRemoves bit.Mask) {
 	scheduledSandboxRemoves.Union(r.scheduledRemoves.Bits())
 	r.scheduledRemoves.Bits().InPlaceIntersection(r.linkedEntities.Bits())
 	r.linkedEntities.Bits().InPlaceDifference(r.scheduledRemoves.Bits())
+	if r.scheduledRemoves.Any() {
+		r.notify(r.scheduledRemoves)
+	}
 }
 
 // Refresh clears scheduled removals.
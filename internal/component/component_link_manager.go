@@ -1,11 +1,12 @@
 package component
 
 import (
+	"reflect"
+
 	"github.com/andrei-cosmin/sandata/array"
 	"github.com/andrei-cosmin/sandata/bit"
 	"github.com/andrei-cosmin/sandata/flag"
 	"github.com/andrei-cosmin/sandecs/component"
-	"github.com/andrei-cosmin/sandecs/entity"
 	"github.com/andrei-cosmin/sandecs/internal/api"
 	"github.com/andrei-cosmin/sandecs/options"
 )
@@ -15,21 +16,31 @@ type linkManager struct {
 	mode              options.Mode
 	poolCapacity      uint
 	defaultLinkerSize uint
-	linkedComponents  map[string]component.Id
-	entityLinker      entity.MaskView
+	componentTypes    map[reflect.Type]component.Id // Typed components, by Go type
+	componentNames    map[string]component.Id       // Typed and dynamic components, by name
+	tags              map[component.Tag]component.Id
+	tagTypes          map[reflect.Type]component.Id // Typed tags, by Go type
+	entityLinker      api.EntityLinker
+	strict            bool
 	componentLinkers  array.Array[api.ComponentLinker]
 	componentIdCursor component.Id
+	tick              uint64
+	unlinkCounts      []uint
 	flag.Flag
 }
 
 // NewLinkManager creates a link manager with pre-allocated capacity.
-func NewLinkManager(mode options.Mode, numEntities, numComponents, poolCapacity uint, entityLinker entity.MaskView) api.ComponentLinkManager {
+func NewLinkManager(mode options.Mode, numEntities, numComponents, poolCapacity uint, entityLinker api.EntityLinker, strict bool) api.ComponentLinkManager {
 	return &linkManager{
 		mode:              mode,
 		poolCapacity:      poolCapacity,
 		defaultLinkerSize: numEntities,
-		linkedComponents:  make(map[string]component.Id),
+		componentTypes:    make(map[reflect.Type]component.Id),
+		componentNames:    make(map[string]component.Id),
+		tags:              make(map[component.Tag]component.Id),
+		tagTypes:          make(map[reflect.Type]component.Id),
 		entityLinker:      entityLinker,
+		strict:            strict,
 		componentLinkers:  *array.New[api.ComponentLinker](numComponents),
 		componentIdCursor: 0,
 		Flag:              flag.New(),
@@ -41,15 +52,48 @@ func (l *linkManager) Get(componentId component.Id) api.ComponentLinker {
 	return l.componentLinkers.Get(componentId)
 }
 
-// UpdateLinks processes all pending component removals.
-func (l *linkManager) UpdateLinks(scheduledSandboxRemoves bit.Mask) {
+// LookupComponent returns the linker of the component registered under the given name (typed or dynamic).
+func (l *linkManager) LookupComponent(name string) (api.ComponentLinker, bool) {
+	if id, ok := l.componentNames[name]; ok {
+		return l.Get(id), true
+	}
+	return nil, false
+}
+
+// LookupTag returns the linker of the given tag.
+func (l *linkManager) LookupTag(tag component.Tag) (api.ComponentLinker, bool) {
+	if id, ok := l.tags[tag]; ok {
+		return l.Get(id), true
+	}
+	return nil, false
+}
+
+// Size returns the number of registered component linkers.
+func (l *linkManager) Size() uint {
+	return l.componentIdCursor
+}
+
+// Tick returns the number of processed updates (used to stamp component changes).
+func (l *linkManager) Tick() uint64 {
+	return l.tick
+}
+
+// UpdateLinks processes all pending component removals and changes.
+// Returns the number of unlinked entities per component ID (the slice is reused by the next call).
+func (l *linkManager) UpdateLinks(scheduledSandboxRemoves bit.Mask) []uint {
+	l.tick++
+	l.unlinkCounts = l.unlinkCounts[:0]
 	for index := range l.componentIdCursor {
 		resolver := l.componentLinkers.Get(index)
+		resolver.CommitChanges(l.tick)
+		linkedCount := resolver.EntityMask().Count()
 		resolver.CleanScheduledEntities(scheduledSandboxRemoves)
+		l.unlinkCounts = append(l.unlinkCounts, linkedCount-resolver.EntityMask().Count())
 		resolver.CleanScheduledInstances()
 		resolver.Refresh()
 	}
 	l.Clear()
+	return l.unlinkCounts
 }
 
 // Accept processes a component registration.
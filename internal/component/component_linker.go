@@ -1,6 +1,8 @@
 package component
 
 import (
+	"errors"
+
 	"github.com/andrei-cosmin/sandecs/component"
 	"github.com/andrei-cosmin/sandecs/entity"
 	"github.com/andrei-cosmin/sandecs/internal/api"
@@ -11,18 +13,21 @@ import (
 // componentLinker manages component instances of type T.
 type componentLinker[T component.Component] struct {
 	baseLinker
+	mode         options.Mode
 	poolCapacity uint
 	components   table[T]
 	additions    *bitset.BitSet
 	onLink       func(*T)
 	onUnlink     func(*T)
+	reset        func(*T) // Initializes linked components (used by dynamic components), if set
 }
 
 func newComponentLinker[T component.Component](
 	mode options.Mode,
 	size, poolCapacity uint,
 	componentId component.Id, componentType string,
-	entityLinker entity.MaskView,
+	entityLinker api.EntityLinker,
+	strict bool,
 	callback func(),
 ) api.ComponentLinker {
 	if poolCapacity <= 0 {
@@ -44,26 +49,135 @@ func newComponentLinker[T component.Component](
 	}
 
 	return &componentLinker[T]{
+		mode:         mode,
 		poolCapacity: poolCapacity,
 		components:   componentTable,
 		additions:    bitset.New(size),
-		baseLinker:   *newBaseLinker(size, componentId, componentType, entityLinker, callback),
+		baseLinker:   *newBaseLinker(size, componentId, componentType, entityLinker, strict, callback),
 	}
 }
 
 // Get returns the component for the entity, or nil if not linked.
 func (r *componentLinker[T]) Get(entityId entity.Id) *T {
+	if r.strict && !r.Has(entityId) {
+		r.fail("get", entityId, r.checkLinked(entityId))
+	}
 	return r.components.get(entityId)
 }
 
-// Link attaches a component to the entity and returns it. Returns nil if already linked.
+// Link attaches a component to the entity and returns it. Returns nil if it can't be linked, panicking in strict mode
+// (entities scheduled for removal are only rejected in strict mode).
 func (r *componentLinker[T]) Link(entityId entity.Id) *T {
-	if r.baseLinker.Link(entityId) {
+	instance, err := r.link(entityId, r.strict)
+	if err != nil {
+		r.fail("link", entityId, err)
+	}
+	return instance
+}
+
+// TryLink attaches a component to the entity and returns it, or a *component.LinkError on failure.
+func (r *componentLinker[T]) TryLink(entityId entity.Id) (*T, error) {
+	instance, err := r.link(entityId, true)
+	if err != nil {
+		return nil, r.linkError("link", entityId, err)
+	}
+	return instance, nil
+}
+
+// link attaches a component to the entity and returns it, returning the reason on failure.
+func (r *componentLinker[T]) link(entityId entity.Id, checkStale bool) (*T, error) {
+	if err := r.baseLinker.link(entityId, checkStale); err != nil {
+		return nil, err
+	}
+	r.components.set(entityId)
+	r.additions.Set(entityId)
+	instance := r.components.get(entityId)
+	if r.reset != nil {
+		r.reset(instance)
+	}
+	return instance, nil
+}
+
+// Mode returns the storage mode of the components.
+func (r *componentLinker[T]) Mode() options.Mode {
+	return r.mode
+}
+
+// NewInstance returns a pointer to a new zero-valued component.
+func (r *componentLinker[T]) NewInstance() any {
+	return new(T)
+}
+
+// GetInstance returns the component for the entity as an untyped pointer, or nil if not linked.
+func (r *componentLinker[T]) GetInstance(entityId entity.Id) any {
+	if !r.Has(entityId) {
+		return nil
+	}
+	return r.components.get(entityId)
+}
+
+// SetInstance links the component if needed and copies the instance into it. Returns false if the entity doesn't exist.
+func (r *componentLinker[T]) SetInstance(entityId entity.Id, instance any) bool {
+	value, ok := instance.(*T)
+	if !ok {
+		return false
+	}
+	if target, _ := r.link(entityId, false); target != nil {
+		*target = *value
+		return true
+	}
+	if !r.Has(entityId) {
+		return false
+	}
+	*r.components.get(entityId) = *value
+	return true
+}
+
+// SetInstances links the component to all the entities at once and copies the instance into each of them.
+func (r *componentLinker[T]) SetInstances(entityIds []entity.Id, instance any) {
+	value, ok := instance.(*T)
+	if !ok {
+		return
+	}
+	for _, entityId := range r.baseLinker.linkBatch(entityIds) {
 		r.components.set(entityId)
 		r.additions.Set(entityId)
-		return r.components.get(entityId)
 	}
-	return nil
+	for _, entityId := range entityIds {
+		if r.Has(entityId) {
+			r.copy(r.components.get(entityId), value)
+		}
+	}
+}
+
+// CloneInstance links the component to the target entity and copies the source entity's component into it.
+func (r *componentLinker[T]) CloneInstance(sourceId, targetId entity.Id) bool {
+	if !r.Has(sourceId) {
+		return false
+	}
+	target, _ := r.link(targetId, false)
+	if target == nil {
+		if !r.Has(targetId) {
+			return false
+		}
+		target = r.components.get(targetId)
+	}
+	r.copy(target, r.components.get(sourceId))
+	return true
+}
+
+// Validate checks the linked entities and that the stored instances match them.
+func (r *componentLinker[T]) Validate() error {
+	return errors.Join(r.baseLinker.Validate(), r.components.validate(r.linkedEntities))
+}
+
+// copy copies the source component into the target, using component.Cloner for deep copies if implemented.
+func (r *componentLinker[T]) copy(target, source *T) {
+	if cloner, ok := any(source).(component.Cloner[T]); ok {
+		*target = cloner.Clone()
+		return
+	}
+	*target = *source
 }
 
 // SetLinkHook sets a callback invoked when a component is linked.
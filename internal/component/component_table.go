@@ -1,6 +1,8 @@
 package component
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 
 	"github.com/andrei-cosmin/sandata/array"
@@ -14,6 +16,7 @@ type table[T any] interface {
 	set(index uint)
 	get(index uint) *T
 	clear(mask bit.Mask, hook func(*T))
+	validate(linked bit.Mask) error
 }
 
 // basicTable stores components in a flat array (Standard mode).
@@ -41,6 +44,10 @@ func (b *basicTable[T]) clear(mask bit.Mask, hook func(*T)) {
 	}
 }
 
+func (b *basicTable[T]) validate(linked bit.Mask) error {
+	return validateContent(&b.content, linked)
+}
+
 // pooledTable stores components with instance reuse (Pooled mode).
 type pooledTable[T any] struct {
 	content array.Array[*T]
@@ -79,6 +86,24 @@ func (p *pooledTable[T]) clear(mask bit.Mask, hook func(*T)) {
 	}
 }
 
+func (p *pooledTable[T]) validate(linked bit.Mask) error {
+	return validateContent(&p.content, linked)
+}
+
+// validateContent checks that instances are present exactly for the linked entities.
+func validateContent[T any](content *array.Array[*T], linked bit.Mask) error {
+	var errs []error
+	for index := range content.Size() {
+		if present := content.Get(index) != nil; present != linked.Test(index) {
+			errs = append(errs, fmt.Errorf("entity %d: instance present %t, linked %t", index, present, !present))
+		}
+	}
+	if index, hasNext := linked.NextSet(content.Size()); hasNext {
+		errs = append(errs, fmt.Errorf("entity %d: linked without instance", index))
+	}
+	return errors.Join(errs...)
+}
+
 // compactTable stores components densely using sparse set (Compact mode).
 type compactTable[T any] struct {
 	cursor  uint
@@ -123,9 +148,29 @@ func (c *compactTable[T]) clear(mask bit.Mask, hook func(*T)) {
 		if slotToRemove != c.cursor {
 			lastEntity := c.reverse.Get(c.cursor)
 			c.content[slotToRemove] = c.content[c.cursor]
+			// Zero the vacated slot, so the moved instance doesn't share its references (slices, maps) with the next instance linked there
+			c.content[c.cursor] = *new(T)
 			c.indices.Set(lastEntity, slotToRemove)
 			c.reverse.Set(slotToRemove, lastEntity)
 		}
 		c.indices.Set(index, c.cursor)
 	}
 }
+
+func (c *compactTable[T]) validate(linked bit.Mask) error {
+	if count := linked.Count(); count != c.cursor {
+		return fmt.Errorf("%d linked entities, %d stored instances", count, c.cursor)
+	}
+	var errs []error
+	for index, hasNext := linked.NextSet(0); hasNext; index, hasNext = linked.NextSet(index + 1) {
+		if index >= c.indices.Size() {
+			errs = append(errs, fmt.Errorf("entity %d: linked without instance", index))
+			continue
+		}
+		slot := c.indices.Get(index)
+		if slot >= c.cursor || c.reverse.Get(slot) != index {
+			errs = append(errs, fmt.Errorf("entity %d: slot %d doesn't map back to the entity", index, slot))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package component
+
+import (
+	"fmt"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+)
+
+// dynamicLinker manages the records of a dynamic component (a component linker of records, initialized with the schema).
+type dynamicLinker struct {
+	*componentLinker[component.Record]
+	schema *component.Schema
+}
+
+// RegisterDynamicLinker registers a linker for the dynamic component described by the schema.
+// Returns component.ErrSchemaConflict if the name is already registered with a different schema (or by another component).
+func RegisterDynamicLinker(schema *component.Schema, componentLinkManager api.ComponentLinkManager) (api.ComponentLinker, error) {
+	l := componentLinkManager.(*linkManager)
+	if existing, ok := l.LookupComponent(schema.Name()); ok {
+		if dynamic, ok := existing.(*dynamicLinker); ok && dynamic.schema.Equal(schema) {
+			return dynamic, nil
+		}
+		return nil, fmt.Errorf("%w: %s", component.ErrSchemaConflict, schema.Name())
+	}
+
+	return registerLinker(l, l.componentNames, schema.Name(), func() api.ComponentLinker {
+		linker := newComponentLinker[component.Record](l.mode, l.defaultLinkerSize, l.poolCapacity, l.componentIdCursor, schema.Name(), l.entityLinker, l.strict, l.Set).(*componentLinker[component.Record])
+		linker.reset = func(record *component.Record) {
+			record.Reset(schema)
+		}
+		return &dynamicLinker{componentLinker: linker, schema: schema}
+	}), nil
+}
+
+// Schema returns the schema of the component.
+func (r *dynamicLinker) Schema() *component.Schema {
+	return r.schema
+}
+
+// NewInstance returns a pointer to a new record, with the zero values of the schema.
+func (r *dynamicLinker) NewInstance() any {
+	record := &component.Record{}
+	record.Reset(r.schema)
+	return record
+}
+
+// SetInstance links the record if needed and copies the given record into it (records are always deep copied).
+// Returns false if the entity doesn't exist or the record has a different schema.
+func (r *dynamicLinker) SetInstance(entityId entity.Id, instance any) bool {
+	record, ok := instance.(*component.Record)
+	if !ok || record.Schema() == nil || !record.Schema().Equal(r.schema) {
+		return false
+	}
+	clone := record.Clone()
+	return r.componentLinker.SetInstance(entityId, &clone)
+}
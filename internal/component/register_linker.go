@@ -1,6 +1,7 @@
 package component
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/andrei-cosmin/sandecs/component"
@@ -8,30 +9,102 @@ import (
 )
 
 // RegisterComponentLinker registers a component linker for type T.
+//
+// Components are identified by their Go type, and named after it with its full package path (e.g. "example.com/game.Position")
+// unless registered with RegisterComponentName.
 func RegisterComponentLinker[T component.Component](componentLinkManager api.ComponentLinkManager) api.ComponentLinker {
-	componentType := reflect.TypeFor[T]().String()
+	componentType := reflect.TypeFor[T]()
 	l := componentLinkManager.(*linkManager)
-	return registerLinker(l, componentType, func() api.ComponentLinker {
-		return newComponentLinker[T](l.mode, l.defaultLinkerSize, l.poolCapacity, l.componentIdCursor, componentType, l.entityLinker, l.Set)
-	})
+	if id, ok := l.componentTypes[componentType]; ok {
+		return l.Get(id)
+	}
+	return registerComponentLinker[T](l, componentType, l.defaultName(componentType))
+}
+
+// RegisterComponentName registers a component linker for type T under the given name (used by snapshots and name-based access).
+// Returns component.ErrNameConflict if the name is used by another component, or if T is already registered under another name.
+func RegisterComponentName[T component.Component](name string, componentLinkManager api.ComponentLinkManager) error {
+	componentType := reflect.TypeFor[T]()
+	l := componentLinkManager.(*linkManager)
+	if id, ok := l.componentTypes[componentType]; ok {
+		if registered := l.Get(id).ComponentType(); registered != name {
+			return fmt.Errorf("%w: %s is registered as %q", component.ErrNameConflict, componentType, registered)
+		}
+		return nil
+	}
+	if _, ok := l.componentNames[name]; ok {
+		return fmt.Errorf("%w: %q", component.ErrNameConflict, name)
+	}
+	registerComponentLinker[T](l, componentType, name)
+	return nil
 }
 
 // RegisterTagLinker registers a tag linker.
 func RegisterTagLinker(tag component.Tag, componentLinkManager api.ComponentLinkManager) api.ComponentLinker {
 	l := componentLinkManager.(*linkManager)
-	return registerLinker(l, tag, func() api.ComponentLinker {
-		return newTagLinker(l.defaultLinkerSize, l.componentIdCursor, tag, l.entityLinker, l.Set)
+	return registerLinker(l, l.tags, tag, func() api.ComponentLinker {
+		return newTagLinker(l.defaultLinkerSize, l.componentIdCursor, tag, l.entityLinker, l.strict, l.Set)
 	})
 }
 
-func registerLinker(linkManager *linkManager, label string, constructor func() api.ComponentLinker) api.ComponentLinker {
-	if id, ok := linkManager.linkedComponents[label]; ok {
+// RegisterTypedTagLinker registers a tag linker for the zero-sized struct type T (panics with component.ErrTagType otherwise).
+//
+// Typed tags are identified by their Go type, and named after it like components. The name is shared with string tags,
+// so data-driven code can refer to a typed tag by name.
+func RegisterTypedTagLinker[T component.Component](componentLinkManager api.ComponentLinkManager) api.ComponentLinker {
+	tagType := reflect.TypeFor[T]()
+	if tagType.Kind() != reflect.Struct || tagType.Size() != 0 {
+		panic(fmt.Errorf("%w: %s", component.ErrTagType, tagType))
+	}
+	l := componentLinkManager.(*linkManager)
+	if id, ok := l.tagTypes[tagType]; ok {
+		return l.Get(id)
+	}
+	linker := RegisterTagLinker(l.defaultTagName(tagType), l)
+	l.tagTypes[tagType] = linker.ComponentId()
+	return linker
+}
+
+func registerComponentLinker[T component.Component](l *linkManager, componentType reflect.Type, name string) api.ComponentLinker {
+	linker := registerLinker(l, l.componentNames, name, func() api.ComponentLinker {
+		return newComponentLinker[T](l.mode, l.defaultLinkerSize, l.poolCapacity, l.componentIdCursor, name, l.entityLinker, l.strict, l.Set)
+	})
+	l.componentTypes[componentType] = linker.ComponentId()
+	return linker
+}
+
+func registerLinker[K comparable](linkManager *linkManager, labels map[K]component.Id, label K, constructor func() api.ComponentLinker) api.ComponentLinker {
+	if id, ok := labels[label]; ok {
 		return linkManager.Get(id)
 	}
 	id := linkManager.componentIdCursor
 	instancedLinker := constructor()
-	linkManager.linkedComponents[label] = id
+	labels[label] = id
 	linkManager.componentLinkers.Set(id, instancedLinker)
 	linkManager.componentIdCursor++
 	return instancedLinker
 }
+
+// defaultName returns the name of a component type: its name qualified by the full package path
+// (e.g. "example.com/game.Position"), unique and independent of the registration order.
+// Panics with component.ErrNameConflict if another component was registered under that name with RegisterComponentName.
+func (l *linkManager) defaultName(componentType reflect.Type) string {
+	name := typeName(componentType)
+	if _, ok := l.componentNames[name]; ok {
+		panic(fmt.Errorf("%w: %q (default name of %s)", component.ErrNameConflict, name, componentType))
+	}
+	return name
+}
+
+// defaultTagName returns the name of a typed tag: its type name qualified by the full package path, like components.
+func (l *linkManager) defaultTagName(tagType reflect.Type) string {
+	return typeName(tagType)
+}
+
+// typeName returns the name of the type qualified by its full package path (or its string form, for unnamed and predeclared types).
+func typeName(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
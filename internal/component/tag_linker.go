@@ -3,6 +3,7 @@ package component
 import (
 	"github.com/andrei-cosmin/sandecs/component"
 	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
 )
 
 // tagLinker manages tag associations for entities (no data storage).
@@ -12,13 +13,13 @@ type tagLinker struct {
 	onUnlink func()
 }
 
-func newTagLinker(size uint, componentId component.Id, componentType string, entityLinker entity.MaskView, callback func()) *tagLinker {
+func newTagLinker(size uint, componentId component.Id, componentType string, entityLinker api.EntityLinker, strict bool, callback func()) *tagLinker {
 	return &tagLinker{
-		baseLinker: *newBaseLinker(size, componentId, componentType, entityLinker, callback),
+		baseLinker: *newBaseLinker(size, componentId, componentType, entityLinker, strict, callback),
 	}
 }
 
-// Link attaches the tag to the entity. Returns false if already linked.
+// Link attaches the tag to the entity. Returns false if it can't be linked, panicking in strict mode.
 func (r *tagLinker) Link(entityId entity.Id) bool {
 	if r.baseLinker.Link(entityId) {
 		if r.onLink != nil {
@@ -29,6 +30,27 @@ func (r *tagLinker) Link(entityId entity.Id) bool {
 	return false
 }
 
+// TryLink attaches the tag to the entity, returning a *component.LinkError on failure.
+func (r *tagLinker) TryLink(entityId entity.Id) error {
+	if err := r.baseLinker.link(entityId, true); err != nil {
+		return r.linkError("link", entityId, err)
+	}
+	if r.onLink != nil {
+		r.onLink()
+	}
+	return nil
+}
+
+// LinkBatch attaches the tag to all the entities, skipping the ones already linked.
+func (r *tagLinker) LinkBatch(entityIds []entity.Id) {
+	linked := r.baseLinker.linkBatch(entityIds)
+	if r.onLink != nil {
+		for range linked {
+			r.onLink()
+		}
+	}
+}
+
 // SetLinkHook sets a callback invoked when a tag is linked.
 func (r *tagLinker) SetLinkHook(onLink func()) {
 	r.onLink = onLink
@@ -37,10 +37,56 @@ func (l *Linker) Link() entity.Id {
 	// Set the corresponding bit in the linked entities bitset
 	l.linkedEntities.Bits().Set(entityId)
 
+	// Flag the linker for update (filters with exclusions match entities without components)
+	l.Set()
+
 	// Return the entity id
 	return entityId
 }
 
+// LinkN method - links count new entities with the sandbox in a single scan of the bitset, returning the entity ids (in ascending order)
+func (l *Linker) LinkN(count uint) []entity.Id {
+	entityIds := make([]entity.Id, 0, count)
+
+	// Find the first clear bit in the linked entities bitset
+	entityId, exists := l.linkedEntities.NextClear(0)
+	for uint(len(entityIds)) < count {
+		// If the entity id does not exist, set it to the length of the linked entities bitset
+		if !exists {
+			entityId = l.linkedEntities.Len()
+		}
+		// Set the corresponding bit in the linked entities bitset
+		l.linkedEntities.Bits().Set(entityId)
+		entityIds = append(entityIds, entityId)
+
+		// Continue the scan from the linked entity id
+		entityId, exists = l.linkedEntities.NextClear(entityId + 1)
+	}
+
+	// Flag the linker for update
+	if count > 0 {
+		l.Set()
+	}
+
+	// Return the entity ids
+	return entityIds
+}
+
+// LinkId method - links the given entity id with the sandbox, returning false if it is already linked
+func (l *Linker) LinkId(entityId entity.Id) bool {
+	// If the entity id is already part of the sandbox, return
+	if l.linkedEntities.Test(entityId) {
+		return false
+	}
+
+	// Set the corresponding bit in the linked entities bitset
+	l.linkedEntities.Bits().Set(entityId)
+
+	// Flag the linker for update
+	l.Set()
+	return true
+}
+
 // Unlink method - unlinks the entity id from the sandbox entirely (this effect will propagate to all the component linkers)
 func (l *Linker) Unlink(entityId entity.Id) {
 	// If the entity id is not part of the sandbox, return
@@ -0,0 +1,88 @@
+package filter
+
+import (
+	"iter"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// setPoolCapacity - the maximum number of released entity sets kept for reuse
+const setPoolCapacity = 16
+
+// entitySet struct - a pooled set of entities, resulting from set operations on views
+//   - registry *Registry - the registry owning the pool
+//   - entities *bit.BitMask - the entities of the set
+//   - released bool - set once the set is returned to the pool
+//   - all iter.Seq[entity.Id] - the iterator over the entities (created once, so that iterating doesn't allocate)
+type entitySet struct {
+	registry *Registry
+	entities *bit.BitMask
+	released bool
+	all      iter.Seq[entity.Id]
+}
+
+// newEntitySet method - creates an empty set of entities with the given size
+func newEntitySet(registry *Registry, size uint) *entitySet {
+	set := &entitySet{registry: registry, entities: bit.NewMask(bitset.New(size))}
+	set.all = func(yield func(entity.Id) bool) {
+		for entityId, hasNext := set.entities.NextSet(0); hasNext; entityId, hasNext = set.entities.NextSet(entityId + 1) {
+			if !yield(entityId) {
+				return
+			}
+		}
+	}
+	return set
+}
+
+// And method - keeps the entities present in the mask
+func (s *entitySet) And(mask bit.Mask) entity.Set {
+	mask.Intersection(s.entities.Bits())
+	return s
+}
+
+// AndNot method - drops the entities present in the mask
+func (s *entitySet) AndNot(mask bit.Mask) entity.Set {
+	mask.Difference(s.entities.Bits())
+	return s
+}
+
+// Count method - retrieves the number of entities
+func (s *entitySet) Count() uint {
+	return s.entities.Count()
+}
+
+// Contains method - checks if the entity is in the set
+func (s *entitySet) Contains(entityId entity.Id) bool {
+	return s.entities.Test(entityId)
+}
+
+// First method - retrieves the lowest entity id
+func (s *entitySet) First() (entity.Id, bool) {
+	return s.entities.NextSet(0)
+}
+
+// Any method - checks if the set isn't empty
+func (s *entitySet) Any() bool {
+	return s.entities.Any()
+}
+
+// All method - iterates over the entities in ascending order
+func (s *entitySet) All() iter.Seq[entity.Id] {
+	return s.all
+}
+
+// EntityMask method - returns the entities of the set as a bitset
+func (s *entitySet) EntityMask() bit.Mask {
+	return s.entities
+}
+
+// Release method - returns the set to the registry pool (subsequent calls have no effect)
+func (s *entitySet) Release() {
+	if s.released {
+		return
+	}
+	s.released = true
+	s.registry.sets.Push(s)
+}
@@ -14,6 +14,9 @@ type CacheId = uint
 
 // Cache struct - filter cache stores the context for a filter (component types , rules, linked entities)
 //   - cacheId CacheId - the id of the filter
+//   - hash string - the hash of the filter rules (registry key)
+//   - references uint - the number of unreleased views referencing the cache
+//   - index int - the position of the cache in the registry caches
 //   - requiredComponentIds []component.Id - the required component ids
 //   - excludedComponentIds []component.Id - the excluded component ids
 //   - unionComponentIds []component.Id - the union component ids
@@ -24,6 +27,9 @@ type CacheId = uint
 //   - Flag: a flag used to mark that the cache is dirty and the expanded entity ids need to be refreshed
 type Cache struct {
 	cacheId              CacheId
+	hash                 string
+	references           uint
+	index                int
 	requiredComponentIds []component.Id
 	excludedComponentIds []component.Id
 	unionComponentIds    []component.Id
@@ -35,8 +41,9 @@ type Cache struct {
 }
 
 // newCache method - creates a new cache with the given size for bitsets and filter rules
-func newCache(size uint, filterRules api.FilterRules) *Cache {
+func newCache(size uint, hash string, filterRules api.FilterRules) *Cache {
 	return &Cache{
+		hash:                 hash,
 		requiredComponentIds: filterRules.RequiredComponentIds(),
 		excludedComponentIds: filterRules.ExcludedComponentIds(),
 		unionComponentIds:    filterRules.UnionComponentIds(),
@@ -66,16 +73,56 @@ func (c *Cache) EntityIds() []entity.Id {
 	return c.entityIdsCache
 }
 
+// RequiredComponentIds method - retrieves the required component ids
+func (c *Cache) RequiredComponentIds() []component.Id {
+	return c.requiredComponentIds
+}
+
+// ExcludedComponentIds method - retrieves the excluded component ids
+func (c *Cache) ExcludedComponentIds() []component.Id {
+	return c.excludedComponentIds
+}
+
+// UnionComponentIds method - retrieves the union component ids
+func (c *Cache) UnionComponentIds() []component.Id {
+	return c.unionComponentIds
+}
+
 // EntityMask method - returns the filtered entities as a bitset
 func (c *Cache) EntityMask() bit.Mask {
 	return c.filteredEntities
 }
 
-// checkForNewAdditions method - checks the entities from the given bitset and adds them to the filtered entities if they are not already included
-func (c *Cache) checkForNewAdditions(entities *bitset.BitSet) {
+// Count method - retrieves the number of filtered entities
+func (c *Cache) Count() uint {
+	return c.filteredEntities.Count()
+}
+
+// Contains method - checks if the entity is included in the filtered entities
+func (c *Cache) Contains(entityId entity.Id) bool {
+	return c.filteredEntities.Test(entityId)
+}
+
+// First method - retrieves the lowest filtered entity id
+func (c *Cache) First() (entity.Id, bool) {
+	return c.filteredEntities.NextSet(0)
+}
+
+// Any method - checks if any entity is filtered
+func (c *Cache) Any() bool {
+	return c.filteredEntities.Any()
+}
+
+// References method - retrieves the number of unreleased views referencing the cache
+func (c *Cache) References() uint {
+	return c.references
+}
+
+// checkForNewAdditions method - checks the entities from the given bitset and adds them to the filtered entities if they are not already included (returns true if any were added)
+func (c *Cache) checkForNewAdditions(entities *bitset.BitSet) bool {
 	// If the entities are already included in the filtered entities, return
 	if c.filteredEntities.Bits().IsSuperSet(entities) {
-		return
+		return false
 	}
 
 	// Mark the cache as dirty
@@ -83,13 +130,14 @@ func (c *Cache) checkForNewAdditions(entities *bitset.BitSet) {
 
 	// Add the entities to the filtered entities bitset
 	c.filteredEntities.Bits().InPlaceUnion(entities)
+	return true
 }
 
-// checkForNewRemovals method -  checks the entities from the given bitset and removes them from the filtered entities if they are included
-func (c *Cache) checkForNewRemovals(entities *bitset.BitSet) {
+// checkForNewRemovals method -  checks the entities from the given bitset and removes them from the filtered entities if they are included (returns true if any were removed)
+func (c *Cache) checkForNewRemovals(entities *bitset.BitSet) bool {
 	// If none of the entities are included in the filtered entities, return
 	if c.filteredEntities.IntersectionCardinality(entities) == 0 {
-		return
+		return false
 	}
 
 	// Mark the cache as dirty
@@ -97,17 +145,20 @@ func (c *Cache) checkForNewRemovals(entities *bitset.BitSet) {
 
 	// Remove the entities from the filtered entities bitset
 	c.filteredEntities.Bits().InPlaceDifference(entities)
+	return true
 }
 
-// checkForNewChanges method - updates the cache with the recomputed filtered entities
-func (c *Cache) checkForNewChanges(recomputedFilteredEntities *bitset.BitSet) {
+// checkForNewChanges method - updates the cache with the recomputed filtered entities (returns true if the filtered entities changed)
+func (c *Cache) checkForNewChanges(recomputedFilteredEntities *bitset.BitSet) bool {
 	// Check if the recomputed entities contain new additions
 	recomputedFilteredEntities.CopyFull(c.linkMaskBuffer)
 	c.linkMaskBuffer.InPlaceDifference(c.filteredEntities.Bits())
-	c.checkForNewAdditions(c.linkMaskBuffer)
+	added := c.checkForNewAdditions(c.linkMaskBuffer)
 
 	// Check if the recomputed entities contain new removals
 	c.filteredEntities.CopyFull(c.unlinkMaskBuffer)
 	c.unlinkMaskBuffer.InPlaceDifference(recomputedFilteredEntities)
-	c.checkForNewRemovals(c.unlinkMaskBuffer)
+	removed := c.checkForNewRemovals(c.unlinkMaskBuffer)
+
+	return added || removed
 }
@@ -1,9 +1,16 @@
 package filter
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandata/pool"
 	"github.com/andrei-cosmin/sandecs/component"
 	"github.com/andrei-cosmin/sandecs/entity"
 	"github.com/andrei-cosmin/sandecs/internal/api"
@@ -14,80 +21,211 @@ import (
 type Registry struct {
 	entityLinker         entity.MaskView
 	componentLinkManager api.ComponentLinkRetriever
-	hashes               map[string]int
+	hashes               map[string]*Cache
 	caches               []*Cache
+	releasedCaches       int
+	views                map[*viewRecord]struct{}
+	viewOrder            uint64
+	sets                 pool.Pool[*entitySet]
 	entitiesBuffer       *bitset.BitSet
 	defaultCacheSize     uint
+	trackViews           bool
 }
 
 // NewRegistry creates a new registry with the given size, entity linker and component link manager.
-func NewRegistry(size uint, entityLinker entity.MaskView, componentLinkManager api.ComponentLinkManager) *Registry {
+// If trackViews is set, views record their creation site and report being garbage collected without being released.
+func NewRegistry(size uint, entityLinker entity.MaskView, componentLinkManager api.ComponentLinkManager, trackViews bool) *Registry {
 	return &Registry{
 		entityLinker:         entityLinker,
 		componentLinkManager: componentLinkManager,
-		hashes:               make(map[string]int),
+		hashes:               make(map[string]*Cache),
 		caches:               make([]*Cache, 0),
+		views:                make(map[*viewRecord]struct{}),
+		sets:                 *pool.New[*entitySet](setPoolCapacity),
 		entitiesBuffer:       bitset.New(size),
 		defaultCacheSize:     size,
+		trackViews:           trackViews,
 	}
 }
 
-// Register registers a filter with the given rules and returns a view.
-func (r *Registry) Register(filterRules api.FilterRules) entity.View {
+// Register registers a filter with the given rules and returns a view (views with identical rules share the cache).
+func (r *Registry) Register(filterRules api.FilterRules) api.FilterView {
 	// Get the hash for the filter rules (component ids are sorted before, so that 2 filters with the same component ids have the same hash)
 	hash := hashFilter(filterRules)
 
-	// If the filter is already registered, return the view of the existing cache
-	if cacheIndex, ok := r.hashes[hash]; ok {
-		return r.caches[cacheIndex]
+	// If the filter is not registered yet, create a new cache for the filter rules and add it to the registry
+	filterCache, ok := r.hashes[hash]
+	if !ok {
+		filterCache = newCache(r.defaultCacheSize, hash, filterRules)
+		filterCache.index = len(r.caches)
+		r.hashes[hash] = filterCache
+		r.caches = append(r.caches, filterCache)
+
+		// Compute the cache right away (filters registered after the first update would stay empty until the next change)
+		filterCache.checkForNewChanges(r.recompute(filterCache))
+	}
+
+	// Create a new view referencing the cache (the view is only recorded if views are tracked)
+	filterCache.references++
+	return newView(r, filterCache)
+}
+
+// release removes the view record, and removes the cache from the registry once no views reference it.
+func (r *Registry) release(record *viewRecord) {
+	// Remove the view record
+	delete(r.views, record)
+
+	// If other views still reference the cache, return
+	filterCache := record.cache
+	filterCache.references--
+	if filterCache.references > 0 {
+		return
 	}
 
-	// Create a new cache for the filter rules and add it to the registry
-	filterCache := newCache(r.defaultCacheSize, filterRules)
+	// Remove the cache from the registry (it will no longer be updated)
+	// The slot is cleared right away, and the caches are compacted by the next update
+	delete(r.hashes, filterCache.hash)
+	r.caches[filterCache.index] = nil
+	r.releasedCaches++
+}
 
-	// Add the hash to the map, with the index of the new cache
-	r.hashes[hash] = len(r.caches)
-	r.caches = append(r.caches, filterCache)
+// compact removes the released caches from the registry caches, keeping the registration order.
+func (r *Registry) compact() {
+	if r.releasedCaches == 0 {
+		return
+	}
+	r.caches = slices.DeleteFunc(r.caches, func(cache *Cache) bool {
+		return cache == nil
+	})
+	for index, cache := range r.caches {
+		cache.index = index
+	}
+	r.releasedCaches = 0
+}
 
-	// Return the view of the new cache
-	return filterCache
+// Views returns the unreleased views (in creation order), only recorded if views are tracked.
+func (r *Registry) Views() []api.ViewInfo {
+	records := slices.SortedFunc(maps.Keys(r.views), func(a, b *viewRecord) int {
+		return cmp.Compare(a.order, b.order)
+	})
+	views := make([]api.ViewInfo, len(records))
+	for index, record := range records {
+		views[index] = api.ViewInfo{
+			Cache:  record.cache,
+			Site:   record.site,
+			Leaked: record.leaked.Load(),
+		}
+	}
+	return views
 }
 
 // UpdateLinks updates the linked entities for all filters.
-func (r *Registry) UpdateLinks() {
-	// Iterate through the caches and update the linked entities
+func (r *Registry) UpdateLinks() uint {
+	changed := uint(0)
+	r.compact()
+
+	// Iterate through the caches, recompute the linked entities and update the cache
 	for _, cache := range r.caches {
-		// If no component ids are required or excluded, clear the linked entities buffer
-		// If only unions are present, only logical ORs will be performed (in which case the masks present in the cache are sufficient)
-		// Performing logical ORs with the empty buffer will not change the result, while having the sandbox entities will give incorrect results
-		if len(cache.requiredComponentIds) == 0 && len(cache.excludedComponentIds) == 0 {
-			r.entitiesBuffer.ClearAll()
-		} else {
-			// In case of required or excluded component ids, copy the linked entities from the entity linker into the buffer
-			r.entityLinker.EntityMask().CopyFull(r.entitiesBuffer)
+		if cache.checkForNewChanges(r.recompute(cache)) {
+			changed++
 		}
+	}
 
-		// Perform logical ANDs for all required component ids
-		for _, requiredId := range cache.requiredComponentIds {
-			var componentResolver = r.componentLinkManager.Get(requiredId)
-			componentResolver.EntityMask().Intersection(r.entitiesBuffer)
-		}
+	// Return the number of changed caches
+	return changed
+}
 
-		// Perform logical XORs for all excluded component ids
-		for _, excludedId := range cache.excludedComponentIds {
-			var componentResolver = r.componentLinkManager.Get(excludedId)
-			componentResolver.EntityMask().Difference(r.entitiesBuffer)
+// Validate checks that every filter cache matches a fresh recomputation of its rules.
+func (r *Registry) Validate() error {
+	r.compact()
+	var errs []error
+	for index, cache := range r.caches {
+		if mismatches := cache.filteredEntities.SymmetricalDifferenceCardinality(r.recompute(cache)); mismatches > 0 {
+			errs = append(errs, fmt.Errorf("filter %d: %d entities differ from the recomputed filter", index, mismatches))
 		}
+	}
+	return errors.Join(errs...)
+}
 
-		// Perform logical ORs for all union component ids
-		for _, unionId := range cache.unionComponentIds {
-			var componentResolver = r.componentLinkManager.Get(unionId)
-			componentResolver.EntityMask().Union(r.entitiesBuffer)
-		}
+// Query computes the entities currently matching the rules, without registering a cache.
+func (r *Registry) Query(filterRules api.FilterRules) []entity.Id {
+	// Evaluate the rules into a new buffer
+	entities := r.evaluate(filterRules, bitset.New(r.entitiesBuffer.Len()))
 
-		// Check for new changes in the linked entities, and update the cache
-		cache.checkForNewChanges(r.entitiesBuffer)
+	// Expand the matched entities into entity ids
+	entityIds := make([]entity.Id, 0, entities.Count())
+	for entityId, hasNext := entities.NextSet(0); hasNext; entityId, hasNext = entities.NextSet(entityId + 1) {
+		entityIds = append(entityIds, entityId)
 	}
+	return entityIds
+}
+
+// newSet retrieves a set of entities (reusing a released one if possible) holding a copy of the given entities.
+func (r *Registry) newSet(entities bit.Mask) *entitySet {
+	set, ok := r.sets.Pop()
+	if !ok {
+		set = newEntitySet(r, r.defaultCacheSize)
+	}
+	set.released = false
+	entities.CopyFull(set.entities.Bits())
+	return set
+}
+
+// recompute computes the entities matching the cache rules (into the shared entities buffer).
+func (r *Registry) recompute(cache *Cache) *bitset.BitSet {
+	return r.evaluate(cache, r.entitiesBuffer)
+}
+
+// evaluate computes the entities matching the rules into the given buffer.
+func (r *Registry) evaluate(filterRules api.FilterRules, buffer *bitset.BitSet) *bitset.BitSet {
+	requiredComponentIds := filterRules.RequiredComponentIds()
+	excludedComponentIds := filterRules.ExcludedComponentIds()
+
+	// If no component ids are required or excluded, clear the linked entities buffer
+	// If only unions are present, only logical ORs will be performed (in which case the masks present in the cache are sufficient)
+	// Performing logical ORs with the empty buffer will not change the result, while having the sandbox entities will give incorrect results
+	if len(requiredComponentIds) == 0 && len(excludedComponentIds) == 0 {
+		buffer.ClearAll()
+	} else {
+		// In case of required or excluded component ids, copy the linked entities from the entity linker into the buffer
+		r.entityLinker.EntityMask().CopyFull(buffer)
+	}
+
+	// Perform logical ANDs for all required component ids
+	for _, requiredId := range requiredComponentIds {
+		var componentResolver = r.componentLinkManager.Get(requiredId)
+		componentResolver.EntityMask().Intersection(buffer)
+	}
+
+	// Perform logical XORs for all excluded component ids
+	for _, excludedId := range excludedComponentIds {
+		var componentResolver = r.componentLinkManager.Get(excludedId)
+		componentResolver.EntityMask().Difference(buffer)
+	}
+
+	// Perform logical ORs for all union component ids
+	for _, unionId := range filterRules.UnionComponentIds() {
+		var componentResolver = r.componentLinkManager.Get(unionId)
+		componentResolver.EntityMask().Union(buffer)
+	}
+
+	// Return the matched entities
+	return buffer
+}
+
+// Caches returns the registered filter caches (in registration order).
+func (r *Registry) Caches() []api.FilterCache {
+	r.compact()
+	caches := make([]api.FilterCache, len(r.caches))
+	for index, cache := range r.caches {
+		caches[index] = cache
+	}
+	return caches
+}
+
+// Size returns the number of registered filter caches.
+func (r *Registry) Size() uint {
+	return uint(len(r.caches) - r.releasedCaches)
 }
 
 // hashFilter hashes the filter rules and returns the hash as a string.
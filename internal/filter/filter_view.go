@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/entity"
+)
+
+// viewRecord struct - tracks an unreleased view (only recorded in the registry if views are tracked)
+//   - cache *Cache - the cache referenced by the view
+//   - order uint64 - the creation order of the view
+//   - site string - the location the view was created at
+//   - leaked atomic.Bool - set if the view was garbage collected without being released
+type viewRecord struct {
+	cache  *Cache
+	order  uint64
+	site   string
+	leaked atomic.Bool
+}
+
+// modulePath is the import path of the module (its root and internal packages are skipped when recording creation sites)
+var modulePath = strings.TrimSuffix(reflect.TypeFor[view]().PkgPath(), "/internal/filter")
+
+// view struct - a reference to a filter cache (views with identical rules share the cache, but are released independently)
+//   - *Cache - the shared cache
+//   - registry *Registry - the registry owning the cache
+//   - record *viewRecord - the record of the view (nil once released)
+//   - cleanup runtime.Cleanup - the leak detection cleanup (only if views are tracked)
+type view struct {
+	*Cache
+	registry *Registry
+	record   *viewRecord
+	cleanup  runtime.Cleanup
+}
+
+// newView method - creates a view of the cache, recording the creation site and watching for leaks if views are tracked
+func newView(registry *Registry, cache *Cache) *view {
+	record := &viewRecord{cache: cache}
+	filterView := &view{Cache: cache, registry: registry, record: record}
+
+	if registry.trackViews {
+		// Record the view in the registry
+		registry.viewOrder++
+		record.order = registry.viewOrder
+		registry.views[record] = struct{}{}
+
+		// Record the first caller outside the library (the sandbox filter functions and typed queries call through different depths)
+		record.site = callerSite()
+		// Mark the record as leaked if the view is garbage collected without being released
+		filterView.cleanup = runtime.AddCleanup(filterView, func(record *viewRecord) {
+			record.leaked.Store(true)
+		}, record)
+	}
+
+	return filterView
+}
+
+// Release method - releases the view, removing the cache from the registry once no views reference it (subsequent calls have no effect)
+func (v *view) Release() {
+	// If the view was already released, return
+	if v.record == nil {
+		return
+	}
+
+	// Stop watching for leaks and release the cache reference
+	v.cleanup.Stop()
+	v.registry.release(v.record)
+	v.record = nil
+}
+
+// And method - retrieves a pooled set of the view entities present in the mask
+func (v *view) And(mask bit.Mask) entity.Set {
+	return v.registry.newSet(v.filteredEntities).And(mask)
+}
+
+// AndNot method - retrieves a pooled set of the view entities not present in the mask
+func (v *view) AndNot(mask bit.Mask) entity.Set {
+	return v.registry.newSet(v.filteredEntities).AndNot(mask)
+}
+
+// callerSite method - retrieves the location of the first caller outside the root and internal packages of the module
+func callerSite() string {
+	var programCounters [32]uintptr
+	frames := runtime.CallersFrames(programCounters[:runtime.Callers(2, programCounters[:])])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, modulePath+".") && !strings.HasPrefix(frame.Function, modulePath+"/internal/") {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
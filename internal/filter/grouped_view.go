@@ -0,0 +1,258 @@
+package filter
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandata/flag"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// GroupedView struct - partitions the entities of another view by a key computed from their component T (kept up to date on each update)
+//   - view entity.View - the underlying view (its entities must have the component T)
+//   - componentLinkManager api.ComponentLinkManager - the component link manager (used for the change tick)
+//   - linker component.Linker[T] - the linker of the component T
+//   - key func(*T) K - computes the group key of a component
+//   - entityKeys map[entity.Id]K - the current key of each grouped entity
+//   - members *bitset.BitSet - the grouped entities
+//   - entered *bitset.BitSet - a bitset buffer for the entities entering the view
+//   - exited *bitset.BitSet - a bitset buffer for the entities exiting the view
+//   - pending *bitset.BitSet - the entities that linked, unlinked or changed the component since the last synchronization
+//   - groups map[K]*group - the non-empty groups, by key (groups are removed once empty)
+//   - keys []K - the group keys, in creation order
+//   - empty *group - the shared empty group (resolved for the keys without entities)
+//   - unwatch func() - unregisters the change watcher
+//   - tick uint64 - the change tick of the last synchronization
+//   - synchronized bool - set once the groups were synchronized for the first time
+type GroupedView[T any, K comparable] struct {
+	view                 entity.View
+	componentLinkManager api.ComponentLinkManager
+	linker               component.Linker[T]
+	key                  func(*T) K
+	entityKeys           map[entity.Id]K
+	members              *bitset.BitSet
+	entered              *bitset.BitSet
+	exited               *bitset.BitSet
+	pending              *bitset.BitSet
+	groups               map[K]*group
+	keys                 []K
+	empty                *group
+	unwatch              func()
+	tick                 uint64
+	synchronized         bool
+}
+
+// group struct - the entities of a grouped view sharing a key
+//   - synchronize func() - synchronizes the owning grouped view
+//   - entities *bit.BitMask - the entities of the group
+//   - entityIdsCache []entity.Id - a cache for the expanded entity ids
+//   - Flag: a flag used to mark that the group is dirty and the expanded entity ids need to be refreshed
+type group struct {
+	synchronize    func()
+	entities       *bit.BitMask
+	entityIdsCache []entity.Id
+	flag.Flag
+}
+
+// groupView struct - a view of the group with the given key (resolved on each access, so that it stays valid as groups are created and removed)
+//   - groupedView *GroupedView[T, K] - the owning grouped view
+//   - key K - the group key
+type groupView[T any, K comparable] struct {
+	groupedView *GroupedView[T, K]
+	key         K
+}
+
+// newGroup method - creates an empty group, synchronized with the given function
+func newGroup(synchronize func()) *group {
+	return &group{
+		synchronize:    synchronize,
+		entities:       bit.NewMask(bitset.New(0)),
+		entityIdsCache: make([]entity.Id, 0),
+		Flag:           flag.New(),
+	}
+}
+
+// NewGroupedView method - creates a partition of the given view's entities, by the key computed from their component T
+func NewGroupedView[T any, K comparable](view entity.View, componentLinkManager api.ComponentLinkManager, linker component.Linker[T], key func(*T) K) *GroupedView[T, K] {
+	groupedView := &GroupedView[T, K]{
+		view:                 view,
+		componentLinkManager: componentLinkManager,
+		linker:               linker,
+		key:                  key,
+		entityKeys:           make(map[entity.Id]K),
+		members:              bitset.New(0),
+		entered:              bitset.New(0),
+		exited:               bitset.New(0),
+		pending:              bitset.New(0),
+		groups:               make(map[K]*group),
+		keys:                 make([]K, 0),
+		empty:                newGroup(func() {}),
+	}
+	groupedView.unwatch = componentLinkManager.Get(linker.ComponentId()).Watch(func(entities bit.Mask) {
+		entities.Union(groupedView.pending)
+	})
+	return groupedView
+}
+
+// Group method - retrieves a view of the entities with the given key (no group is created for keys without entities)
+func (v *GroupedView[T, K]) Group(key K) entity.View {
+	return &groupView[T, K]{groupedView: v, key: key}
+}
+
+// Keys method - retrieves the keys of the non-empty groups, in creation order
+func (v *GroupedView[T, K]) Keys() []K {
+	v.synchronize()
+	return slices.Clone(v.keys)
+}
+
+// All method - iterates over the non-empty groups, in creation order (the groups are yielded as by Group)
+func (v *GroupedView[T, K]) All() iter.Seq2[K, entity.View] {
+	return func(yield func(K, entity.View) bool) {
+		for _, key := range v.Keys() {
+			if !yield(key, v.Group(key)) {
+				return
+			}
+		}
+	}
+}
+
+// Release method - stops tracking the component changes (the groups are no longer updated, subsequent calls have no effect)
+func (v *GroupedView[T, K]) Release() {
+	if v.unwatch == nil {
+		return
+	}
+	v.unwatch()
+	v.unwatch = nil
+}
+
+// resolve method - retrieves the group with the given key, or the shared empty group if no entity has the key
+func (v *GroupedView[T, K]) resolve(key K) *group {
+	v.synchronize()
+	if entityGroup, ok := v.groups[key]; ok {
+		return entityGroup
+	}
+	return v.empty
+}
+
+// group method - retrieves the group with the given key (creating it if needed)
+func (v *GroupedView[T, K]) group(key K) *group {
+	entityGroup, ok := v.groups[key]
+	if !ok {
+		entityGroup = newGroup(v.synchronize)
+		v.groups[key] = entityGroup
+		v.keys = append(v.keys, key)
+	}
+	return entityGroup
+}
+
+// synchronize method - moves the entities that entered, exited or changed their component since the last update into their groups,
+// and removes the groups left empty (the underlying view and the change ticks only change on updates, so the groups are synchronized at most once per update)
+func (v *GroupedView[T, K]) synchronize() {
+	// If the groups were already synchronized for the current update (or the view was released), return
+	tick := v.componentLinkManager.Tick()
+	if (v.synchronized && tick == v.tick) || v.unwatch == nil {
+		return
+	}
+	v.tick = tick
+	v.synchronized = true
+
+	// If no entity entered, exited or changed, the groups are unchanged
+	mask := v.view.EntityMask()
+	if v.pending.None() && mask.SymmetricalDifferenceCardinality(v.members) == 0 {
+		return
+	}
+	removed := false
+
+	// Entities that exited the view: remove them from their groups
+	v.members.CopyFull(v.exited)
+	mask.Difference(v.exited)
+	for entityId, hasNext := v.exited.NextSet(0); hasNext; entityId, hasNext = v.exited.NextSet(entityId + 1) {
+		v.groups[v.entityKeys[entityId]].remove(entityId)
+		delete(v.entityKeys, entityId)
+		v.members.Clear(entityId)
+		removed = true
+	}
+
+	// Grouped entities whose component changed: move them if their key changed
+	for entityId, hasNext := v.pending.NextSet(0); hasNext; entityId, hasNext = v.pending.NextSet(entityId + 1) {
+		if !v.members.Test(entityId) {
+			continue
+		}
+		if key, newKey := v.entityKeys[entityId], v.key(v.linker.Get(entityId)); newKey != key {
+			v.groups[key].remove(entityId)
+			v.group(newKey).add(entityId)
+			v.entityKeys[entityId] = newKey
+			removed = true
+		}
+	}
+	v.pending.ClearAll()
+
+	// Entities that entered the view: add them to their groups
+	mask.CopyFull(v.entered)
+	v.entered.InPlaceDifference(v.members)
+	for entityId, hasNext := v.entered.NextSet(0); hasNext; entityId, hasNext = v.entered.NextSet(entityId + 1) {
+		key := v.key(v.linker.Get(entityId))
+		v.group(key).add(entityId)
+		v.entityKeys[entityId] = key
+		v.members.Set(entityId)
+	}
+
+	// Remove the groups left empty (their keys resolve to the shared empty group)
+	if removed {
+		v.keys = slices.DeleteFunc(v.keys, func(key K) bool {
+			if v.groups[key].entities.None() {
+				delete(v.groups, key)
+				return true
+			}
+			return false
+		})
+	}
+}
+
+// EntityIds method - retrieves the entities of the group (as a slice of entity ids converted from the bitset)
+func (g *group) EntityIds() []entity.Id {
+	g.synchronize()
+
+	// If the group is dirty, refresh the entity ids
+	if g.IsSet() {
+		g.Clear()
+		g.entityIdsCache = g.entityIdsCache[:0]
+		for entityId, hasNext := g.entities.NextSet(0); hasNext; entityId, hasNext = g.entities.NextSet(entityId + 1) {
+			g.entityIdsCache = append(g.entityIdsCache, entityId)
+		}
+	}
+
+	return g.entityIdsCache
+}
+
+// EntityMask method - returns the entities of the group as a bitset
+func (g *group) EntityMask() bit.Mask {
+	g.synchronize()
+	return g.entities
+}
+
+// add method - adds the entity to the group
+func (g *group) add(entityId entity.Id) {
+	g.entities.Bits().Set(entityId)
+	g.Set()
+}
+
+// remove method - removes the entity from the group
+func (g *group) remove(entityId entity.Id) {
+	g.entities.Bits().Clear(entityId)
+	g.Set()
+}
+
+// EntityIds method - retrieves the entities of the group with the key
+func (v *groupView[T, K]) EntityIds() []entity.Id {
+	return v.groupedView.resolve(v.key).EntityIds()
+}
+
+// EntityMask method - returns the entities of the group with the key as a bitset
+func (v *groupView[T, K]) EntityMask() bit.Mask {
+	return v.groupedView.resolve(v.key).EntityMask()
+}
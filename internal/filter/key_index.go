@@ -0,0 +1,174 @@
+package filter
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// KeyIndex struct - maps unique keys computed from the component T to entities (synchronized at the end of each update)
+//   - changes api.ComponentLinker - the untyped linker of the component T (used for the linked entities and change tracking)
+//   - linker component.Linker[T] - the linker of the component T
+//   - key func(*T) K - computes the key of a component
+//   - entityKeys map[entity.Id]K - the current key of each indexed entity
+//   - owners map[K]entity.Id - the entity owning each key (the first one claiming it)
+//   - claimants map[K][]entity.Id - the other entities claiming an owned key (in claim order)
+//   - onConflict func(key K, owner, claimant entity.Id) - the conflict callback (optional)
+//   - pending *bitset.BitSet - the entities that linked, unlinked or changed the component since the last synchronization
+//   - initialized bool - set once the linked entities were indexed (deferred, so that OnConflict can be set first)
+//   - unwatch func() - unregisters the change watcher
+//   - removeHook func() - unregisters the synchronization hook
+type KeyIndex[T any, K comparable] struct {
+	changes     api.ComponentLinker
+	linker      component.Linker[T]
+	key         func(*T) K
+	entityKeys  map[entity.Id]K
+	owners      map[K]entity.Id
+	claimants   map[K][]entity.Id
+	onConflict  func(key K, owner, claimant entity.Id)
+	pending     *bitset.BitSet
+	initialized bool
+	unwatch     func()
+	removeHook  func()
+}
+
+// NewKeyIndex method - creates an index of the entities linked to the component T, by the key computed from the component
+// (registering its synchronization with onUpdate, and indexing the entities already linked on first use)
+func NewKeyIndex[T any, K comparable](componentLinkManager api.ComponentLinkManager, linker component.Linker[T], key func(*T) K, onUpdate func(hook func()) (remove func())) *KeyIndex[T, K] {
+	index := &KeyIndex[T, K]{
+		changes:    componentLinkManager.Get(linker.ComponentId()),
+		linker:     linker,
+		key:        key,
+		entityKeys: make(map[entity.Id]K),
+		owners:     make(map[K]entity.Id),
+		claimants:  make(map[K][]entity.Id),
+		pending:    bitset.New(0),
+	}
+	index.unwatch = index.changes.Watch(func(entities bit.Mask) {
+		entities.Union(index.pending)
+	})
+	index.removeHook = onUpdate(index.Synchronize)
+	return index
+}
+
+// Lookup method - retrieves the entity owning the key
+func (i *KeyIndex[T, K]) Lookup(key K) (entity.Id, bool) {
+	i.initialize()
+	entityId, ok := i.owners[key]
+	return entityId, ok
+}
+
+// Conflicts method - describes the keys claimed by several entities, ordered by owner (nil if all keys are unique)
+func (i *KeyIndex[T, K]) Conflicts() error {
+	i.initialize()
+	keys := slices.SortedFunc(maps.Keys(i.claimants), func(a, b K) int {
+		return cmp.Compare(i.owners[a], i.owners[b])
+	})
+	errs := make([]error, 0, len(keys))
+	for _, key := range keys {
+		errs = append(errs, fmt.Errorf("%w %v: owned by entity %d, claimed by entities %v", component.ErrDuplicateKey, key, i.owners[key], i.claimants[key]))
+	}
+	return errors.Join(errs...)
+}
+
+// OnConflict method - sets the conflict callback
+func (i *KeyIndex[T, K]) OnConflict(onConflict func(key K, owner, claimant entity.Id)) {
+	i.onConflict = onConflict
+}
+
+// Release method - stops synchronizing the index (subsequent calls have no effect)
+func (i *KeyIndex[T, K]) Release() {
+	if i.unwatch == nil {
+		return
+	}
+	i.unwatch()
+	i.removeHook()
+	i.unwatch, i.removeHook = nil, nil
+}
+
+// Synchronize method - re-indexes the entities that linked, unlinked or changed their component T since the last synchronization
+func (i *KeyIndex[T, K]) Synchronize() {
+	i.initialize()
+	mask := i.changes.EntityMask()
+
+	// Indexed entities: release the keys of the ones that unlinked the component or changed their key
+	for entityId, hasNext := i.pending.NextSet(0); hasNext; entityId, hasNext = i.pending.NextSet(entityId + 1) {
+		if key, ok := i.entityKeys[entityId]; ok && (!mask.Test(entityId) || i.key(i.linker.Get(entityId)) != key) {
+			i.release(key, entityId)
+			delete(i.entityKeys, entityId)
+		}
+	}
+
+	// Linked entities: index the ones that linked the component or changed their key (once the released keys are free)
+	for entityId, hasNext := i.pending.NextSet(0); hasNext; entityId, hasNext = i.pending.NextSet(entityId + 1) {
+		if _, ok := i.entityKeys[entityId]; !ok && mask.Test(entityId) {
+			i.claim(i.key(i.linker.Get(entityId)), entityId)
+		}
+	}
+
+	i.pending.ClearAll()
+}
+
+// initialize method - indexes all the linked entities on first use (the changes recorded so far are included)
+func (i *KeyIndex[T, K]) initialize() {
+	if i.initialized {
+		return
+	}
+	i.initialized = true
+	i.pending.ClearAll()
+
+	mask := i.changes.EntityMask()
+	for entityId, hasNext := mask.NextSet(0); hasNext; entityId, hasNext = mask.NextSet(entityId + 1) {
+		i.claim(i.key(i.linker.Get(entityId)), entityId)
+	}
+}
+
+// claim method - assigns the key to the entity (if the key is already owned, the entity is recorded as a claimant and the conflict is reported)
+func (i *KeyIndex[T, K]) claim(key K, entityId entity.Id) {
+	i.entityKeys[entityId] = key
+
+	// If the key is free, the entity owns it
+	owner, ok := i.owners[key]
+	if !ok {
+		i.owners[key] = entityId
+		return
+	}
+
+	// Otherwise, record the claimant and report the conflict
+	i.claimants[key] = append(i.claimants[key], entityId)
+	if i.onConflict != nil {
+		i.onConflict(key, owner, entityId)
+	}
+}
+
+// release method - removes the entity's claim on the key (if the entity owned the key, the first claimant becomes the owner)
+func (i *KeyIndex[T, K]) release(key K, entityId entity.Id) {
+	claimants := i.claimants[key]
+
+	// If the entity is a claimant, remove it from the claimants
+	if i.owners[key] != entityId {
+		claimants = slices.DeleteFunc(claimants, func(claimantId entity.Id) bool {
+			return claimantId == entityId
+		})
+	} else if len(claimants) > 0 {
+		// If the entity owns the key, transfer it to the first claimant
+		i.owners[key] = claimants[0]
+		claimants = claimants[1:]
+	} else {
+		delete(i.owners, key)
+	}
+
+	if len(claimants) == 0 {
+		delete(i.claimants, key)
+	} else {
+		i.claimants[key] = claimants
+	}
+}
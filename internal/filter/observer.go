@@ -0,0 +1,79 @@
+package filter
+
+import (
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// Observer struct - reports the entities that linked, changed or unlinked the component T (synchronized at the end of each update)
+//   - changes api.ComponentLinker - the untyped linker of the component T (used for the linked entities and change tracking)
+//   - linker component.Linker[T] - the linker of the component T
+//   - onChange func(entity.Id, *T) - called for the entities that linked or changed the component
+//   - onUnlink func(entity.Id) - called for the entities that unlinked the component
+//   - members *bitset.BitSet - the entities reported as linked
+//   - pending *bitset.BitSet - the entities that linked, unlinked or changed the component since the last synchronization
+//   - unwatch func() - unregisters the change watcher
+//   - removeHook func() - unregisters the synchronization hook
+type Observer[T any] struct {
+	changes    api.ComponentLinker
+	linker     component.Linker[T]
+	onChange   func(entity.Id, *T)
+	onUnlink   func(entity.Id)
+	members    *bitset.BitSet
+	pending    *bitset.BitSet
+	unwatch    func()
+	removeHook func()
+}
+
+// NewObserver method - creates an observer of the component T, registering its synchronization with onUpdate
+// (the entities already linked are reported right away)
+func NewObserver[T any](componentLinkManager api.ComponentLinkManager, linker component.Linker[T], onChange func(entity.Id, *T), onUnlink func(entity.Id), onUpdate func(hook func()) (remove func())) *Observer[T] {
+	observer := &Observer[T]{
+		changes:  componentLinkManager.Get(linker.ComponentId()),
+		linker:   linker,
+		onChange: onChange,
+		onUnlink: onUnlink,
+		members:  bitset.New(0),
+		pending:  bitset.New(0),
+	}
+	observer.unwatch = observer.changes.Watch(func(entities bit.Mask) {
+		entities.Union(observer.pending)
+	})
+	observer.removeHook = onUpdate(observer.Synchronize)
+
+	// Report the entities already linked
+	mask := observer.changes.EntityMask()
+	for entityId, hasNext := mask.NextSet(0); hasNext; entityId, hasNext = mask.NextSet(entityId + 1) {
+		observer.members.Set(entityId)
+		onChange(entityId, linker.Get(entityId))
+	}
+	return observer
+}
+
+// Release method - stops reporting the changes (subsequent calls have no effect)
+func (o *Observer[T]) Release() {
+	if o.unwatch == nil {
+		return
+	}
+	o.unwatch()
+	o.removeHook()
+	o.unwatch, o.removeHook = nil, nil
+}
+
+// Synchronize method - reports the entities that linked, changed or unlinked the component since the last synchronization
+func (o *Observer[T]) Synchronize() {
+	mask := o.changes.EntityMask()
+	for entityId, hasNext := o.pending.NextSet(0); hasNext; entityId, hasNext = o.pending.NextSet(entityId + 1) {
+		if mask.Test(entityId) {
+			o.members.Set(entityId)
+			o.onChange(entityId, o.linker.Get(entityId))
+		} else if o.members.Test(entityId) {
+			o.members.Clear(entityId)
+			o.onUnlink(entityId)
+		}
+	}
+	o.pending.ClearAll()
+}
@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// SortedView struct - a view of the entities of another view, ordered by their component T (kept sorted incrementally)
+//   - view entity.View - the underlying view (its entities must have the component T)
+//   - componentLinkManager api.ComponentLinkManager - the component link manager (used for the change tick)
+//   - linker component.Linker[T] - the linker of the component T
+//   - less func(a, b *T) bool - the ordering of the components
+//   - members *bitset.BitSet - the entities currently present in the sorted entity ids
+//   - entered *bitset.BitSet - a bitset buffer for the entities entering the view
+//   - removals *bitset.BitSet - a bitset buffer for the entities exiting (or moving in) the sorted entity ids
+//   - pending *bitset.BitSet - the entities that linked, unlinked or changed the component since the last synchronization
+//   - insertions []entity.Id - a buffer for the entities entering (or moving in) the sorted entity ids
+//   - entityIds []entity.Id - the sorted entity ids
+//   - unwatch func() - unregisters the change watcher
+//   - tick uint64 - the change tick of the last synchronization
+//   - synchronized bool - set once the sorted entity ids were synchronized (for the change tick)
+type SortedView[T any] struct {
+	view                 entity.View
+	componentLinkManager api.ComponentLinkManager
+	linker               component.Linker[T]
+	less                 func(a, b *T) bool
+	members              *bitset.BitSet
+	entered              *bitset.BitSet
+	removals             *bitset.BitSet
+	pending              *bitset.BitSet
+	insertions           []entity.Id
+	entityIds            []entity.Id
+	unwatch              func()
+	tick                 uint64
+	synchronized         bool
+}
+
+// NewSortedView method - creates a view of the given view's entities, ordered by their component T using the given less function
+func NewSortedView[T any](view entity.View, componentLinkManager api.ComponentLinkManager, linker component.Linker[T], less func(a, b *T) bool) *SortedView[T] {
+	sortedView := &SortedView[T]{
+		view:                 view,
+		componentLinkManager: componentLinkManager,
+		linker:               linker,
+		less:                 less,
+		members:              bitset.New(0),
+		entered:              bitset.New(0),
+		removals:             bitset.New(0),
+		pending:              bitset.New(0),
+		insertions:           make([]entity.Id, 0),
+		entityIds:            make([]entity.Id, 0),
+	}
+	sortedView.unwatch = componentLinkManager.Get(linker.ComponentId()).Watch(func(entities bit.Mask) {
+		entities.Union(sortedView.pending)
+	})
+	return sortedView
+}
+
+// EntityIds method - retrieves the entities ordered by their component T (synchronizing the order with the underlying view first)
+func (v *SortedView[T]) EntityIds() []entity.Id {
+	v.synchronize()
+	return v.entityIds
+}
+
+// EntityMask method - returns the entities of the underlying view as a bitset
+func (v *SortedView[T]) EntityMask() bit.Mask {
+	return v.view.EntityMask()
+}
+
+// Release method - stops tracking the component changes (the order is no longer updated, subsequent calls have no effect)
+func (v *SortedView[T]) Release() {
+	if v.unwatch == nil {
+		return
+	}
+	v.unwatch()
+	v.unwatch = nil
+}
+
+// synchronize method - updates the sorted entity ids with the entities that entered or exited the underlying view,
+// and moves the entities whose component changed since the last synchronization (only those entities are re-sorted)
+// (the underlying view and the change ticks only change on updates, so the order is synchronized at most once per update)
+func (v *SortedView[T]) synchronize() {
+	// If the order was already synchronized for the current update (or the view was released), return
+	tick := v.componentLinkManager.Tick()
+	if (v.synchronized && tick == v.tick) || v.unwatch == nil {
+		return
+	}
+	v.tick = tick
+	v.synchronized = true
+
+	// If no entity entered, exited or changed, the order is unchanged
+	mask := v.view.EntityMask()
+	if v.pending.None() && mask.SymmetricalDifferenceCardinality(v.members) == 0 {
+		return
+	}
+	v.insertions = v.insertions[:0]
+
+	// Entities that exited the view: remove them
+	v.members.CopyFull(v.removals)
+	mask.Difference(v.removals)
+	v.members.InPlaceDifference(v.removals)
+
+	// Sorted entities whose component changed: move them
+	for entityId, hasNext := v.pending.NextSet(0); hasNext; entityId, hasNext = v.pending.NextSet(entityId + 1) {
+		if v.members.Test(entityId) {
+			v.removals.Set(entityId)
+			v.insertions = append(v.insertions, entityId)
+		}
+	}
+	v.pending.ClearAll()
+
+	// Entities that entered the view: insert them
+	mask.CopyFull(v.entered)
+	v.entered.InPlaceDifference(v.members)
+	for entityId, hasNext := v.entered.NextSet(0); hasNext; entityId, hasNext = v.entered.NextSet(entityId + 1) {
+		v.members.Set(entityId)
+		v.insertions = append(v.insertions, entityId)
+	}
+
+	// Remove the exiting and moving entities
+	if v.removals.Any() {
+		v.entityIds = slices.DeleteFunc(v.entityIds, func(entityId entity.Id) bool {
+			return v.removals.Test(entityId)
+		})
+	}
+
+	// If many entities are inserted, sort all the entities at once
+	if len(v.insertions) > len(v.entityIds)/4 {
+		v.entityIds = append(v.entityIds, v.insertions...)
+		slices.SortFunc(v.entityIds, v.compare)
+		return
+	}
+
+	// Otherwise, insert each entity at its position
+	for _, entityId := range v.insertions {
+		index, _ := slices.BinarySearchFunc(v.entityIds, entityId, v.compare)
+		v.entityIds = slices.Insert(v.entityIds, index, entityId)
+	}
+}
+
+// compare method - orders 2 entities by their component T (entities with equal components are ordered by id)
+func (v *SortedView[T]) compare(a, b entity.Id) int {
+	componentA, componentB := v.linker.Get(a), v.linker.Get(b)
+	if v.less(componentA, componentB) {
+		return -1
+	}
+	if v.less(componentB, componentA) {
+		return 1
+	}
+	return cmp.Compare(a, b)
+}
@@ -1,14 +1,18 @@
 package sandbox
 
 import (
+	"fmt"
 	"slices"
+	"time"
 
 	"github.com/andrei-cosmin/sandecs/component"
 	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/inspect"
 	"github.com/andrei-cosmin/sandecs/internal/api"
 	internalComponent "github.com/andrei-cosmin/sandecs/internal/component"
 	internalEntity "github.com/andrei-cosmin/sandecs/internal/entity"
 	internalFilter "github.com/andrei-cosmin/sandecs/internal/filter"
+	internalSnapshot "github.com/andrei-cosmin/sandecs/internal/snapshot"
 	"github.com/andrei-cosmin/sandecs/options"
 )
 
@@ -17,17 +21,28 @@ type Sandbox struct {
 	entityLinker         api.EntityLinker
 	componentLinkManager api.ComponentLinkManager
 	filterRegistry       api.FilterRegistry
+	migrations           *internalSnapshot.Migrations
+	checkpoints          *internalSnapshot.Checkpoints
+	updateHooks          []*updateHook
+	hooksRemoved         bool
+	instrumentations     []inspect.Instrumentation
+	frameStats           frameStats
+	stats                inspect.Stats
+	flags                options.Flag
 }
 
 // New creates a sandbox with pre-allocated capacity.
-func New(mode options.Mode, numEntities, numComponents, poolCapacity uint) *Sandbox {
+func New(mode options.Mode, numEntities, numComponents, poolCapacity uint, flags options.Flag) *Sandbox {
 	entityLinker := internalEntity.NewLinker(numEntities)
-	componentLinkManager := internalComponent.NewLinkManager(mode, numEntities, numComponents, poolCapacity, entityLinker)
-	filterRegistry := internalFilter.NewRegistry(numEntities, entityLinker, componentLinkManager)
+	componentLinkManager := internalComponent.NewLinkManager(mode, numEntities, numComponents, poolCapacity, entityLinker, flags&options.Strict != 0)
+	filterRegistry := internalFilter.NewRegistry(numEntities, entityLinker, componentLinkManager, flags&options.TrackViews != 0)
 	return &Sandbox{
 		entityLinker:         entityLinker,
 		componentLinkManager: componentLinkManager,
 		filterRegistry:       filterRegistry,
+		migrations:           internalSnapshot.NewMigrations(),
+		checkpoints:          internalSnapshot.NewCheckpoints(),
+		flags:                flags,
 	}
 }
 
@@ -43,7 +58,39 @@ func (s *Sandbox) LinkEntity() entity.Id {
 
 // UnlinkEntity schedules entity removal.
 func (s *Sandbox) UnlinkEntity(entityId entity.Id) {
+	if err := s.unlinkEntity(entityId); err != nil && s.IsStrict() {
+		panic(fmt.Errorf("unlink entity %d: %w", entityId, err))
+	}
+}
+
+// TryUnlinkEntity schedules entity removal, returning the reason on failure.
+func (s *Sandbox) TryUnlinkEntity(entityId entity.Id) error {
+	if err := s.unlinkEntity(entityId); err != nil {
+		return fmt.Errorf("unlink entity %d: %w", entityId, err)
+	}
+	return nil
+}
+
+// unlinkEntity schedules entity removal, returning the reason on failure (unwrapped, so the default path doesn't allocate).
+func (s *Sandbox) unlinkEntity(entityId entity.Id) error {
+	if !s.IsEntityLinked(entityId) {
+		return component.ErrEntityNotLinked
+	}
+	if s.entityLinker.GetScheduledRemoves().Test(entityId) {
+		return component.ErrStaleEntity
+	}
 	s.entityLinker.Unlink(entityId)
+	return nil
+}
+
+// IsStrict returns true if API misuse is reported (see options.Strict).
+func (s *Sandbox) IsStrict() bool {
+	return s.flags&options.Strict != 0
+}
+
+// IsStarted returns true once the first frame ended.
+func (s *Sandbox) IsStarted() bool {
+	return s.frameStats.frame > 0
 }
 
 // IsEntityLinked returns true if the entity exists.
@@ -51,12 +98,56 @@ func (s *Sandbox) IsEntityLinked(entityId entity.Id) bool {
 	return s.entityLinker.EntityMask().Test(entityId)
 }
 
-// Update processes all pending changes.
+// Update processes all pending changes, recording the counts and phase timings in the frame statistics.
 func (s *Sandbox) Update() {
+	stats := &s.frameStats
+	start := time.Now()
+	linkedCount := s.entityLinker.EntityMask().Count()
 	s.entityLinker.Update()
-	s.componentLinkManager.UpdateLinks(s.entityLinker.GetScheduledRemoves())
-	s.filterRegistry.UpdateLinks()
+	stats.entitiesUnlinked += linkedCount - s.entityLinker.EntityMask().Count()
+
+	entitiesDone := time.Now()
+	stats.recordUnlinks(s.componentLinkManager.UpdateLinks(s.entityLinker.GetScheduledRemoves()))
+
+	componentsDone := time.Now()
+	stats.filtersChanged += s.filterRegistry.UpdateLinks()
+	stats.filtersRecomputed += s.filterRegistry.Size()
 	s.entityLinker.Refresh()
+
+	stats.phases.Entities += entitiesDone.Sub(start)
+	stats.phases.Components += componentsDone.Sub(entitiesDone)
+	stats.phases.Filters += time.Since(componentsDone)
+}
+
+// updateHook is a hook called at the end of each frame (removed hooks are skipped until the end of the frame).
+type updateHook struct {
+	run     func()
+	removed bool
+}
+
+// OnUpdate registers a hook called at the end of each frame. Returns a function unregistering the hook.
+func (s *Sandbox) OnUpdate(hook func()) (remove func()) {
+	entry := &updateHook{run: hook}
+	s.updateHooks = append(s.updateHooks, entry)
+	return func() {
+		entry.removed = true
+		s.hooksRemoved = true
+	}
+}
+
+// runUpdateHooks calls the registered hooks, then drops the removed ones (hooks can be removed while running).
+func (s *Sandbox) runUpdateHooks() {
+	for index := 0; index < len(s.updateHooks); index++ {
+		if entry := s.updateHooks[index]; !entry.removed {
+			entry.run()
+		}
+	}
+	if s.hooksRemoved {
+		s.updateHooks = slices.DeleteFunc(s.updateHooks, func(entry *updateHook) bool {
+			return entry.removed
+		})
+		s.hooksRemoved = false
+	}
 }
 
 // Accept processes a component registration.
@@ -65,7 +156,39 @@ func (s *Sandbox) Accept(registration api.Registration) {
 }
 
 // LinkFilter creates a filter view from the given rules.
-func LinkFilter(s *Sandbox, rules []Rule) entity.View {
+func LinkFilter(s *Sandbox, rules []Rule) api.FilterView {
+	return s.filterRegistry.Register(acceptRules(s, rules))
+}
+
+// QueryOnce returns the entities currently matching the given rules (including links not yet processed by Update),
+// without registering a filter cache.
+func QueryOnce(s *Sandbox, rules []Rule) []entity.Id {
+	return s.filterRegistry.Query(acceptRules(s, rules))
+}
+
+// SortedView creates a view of the given view's entities, ordered by their component T.
+func SortedView[T component.Component](s *Sandbox, view entity.View, linker component.Linker[T], less func(a, b *T) bool) *internalFilter.SortedView[T] {
+	return internalFilter.NewSortedView(view, s.componentLinkManager, linker, less)
+}
+
+// GroupBy creates a partition of the given view's entities, by the key computed from their component T.
+func GroupBy[T component.Component, K comparable](s *Sandbox, view entity.View, linker component.Linker[T], key func(*T) K) *internalFilter.GroupedView[T, K] {
+	return internalFilter.NewGroupedView(view, s.componentLinkManager, linker, key)
+}
+
+// Index creates an index of the entities linked to the component T, by the key computed from the component
+// (synchronized at the end of each frame).
+func Index[T component.Component, K comparable](s *Sandbox, linker component.Linker[T], key func(*T) K) *internalFilter.KeyIndex[T, K] {
+	return internalFilter.NewKeyIndex(s.componentLinkManager, linker, key, s.OnUpdate)
+}
+
+// Observe registers an observer of the component T, synchronized at the end of each frame.
+func Observe[T component.Component](s *Sandbox, linker component.Linker[T], onChange func(entity.Id, *T), onUnlink func(entity.Id)) *internalFilter.Observer[T] {
+	return internalFilter.NewObserver(s.componentLinkManager, linker, onChange, onUnlink, s.OnUpdate)
+}
+
+// acceptRules registers the rule components and groups their IDs by rule type (sorted, so identical filters hash the same).
+func acceptRules(s *Sandbox, rules []Rule) *filterRules {
 	ruleSets := make([][]component.Id, SetSize)
 	for ruleSetIndex := range SetSize {
 		ruleSets[ruleSetIndex] = make([]component.Id, 0)
@@ -80,9 +203,9 @@ func LinkFilter(s *Sandbox, rules []Rule) entity.View {
 		slices.Sort(ruleSets[ruleSetIndex])
 	}
 
-	return s.filterRegistry.Register(&filterRules{
+	return &filterRules{
 		match:   ruleSets[Match],
 		exclude: ruleSets[Exclude],
 		union:   ruleSets[Union],
-	})
+	}
 }
@@ -0,0 +1,30 @@
+package sandbox
+
+import (
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+)
+
+// CloneEntity creates a new entity with copies of the source entity's components and tags.
+// Returns false, without linking an entity, if the source doesn't exist.
+func (s *Sandbox) CloneEntity(sourceId entity.Id) (entity.Id, bool) {
+	if !s.IsEntityLinked(sourceId) {
+		return 0, false
+	}
+	targetId := s.entityLinker.Link()
+
+	for componentId := range component.Id(s.componentLinkManager.Size()) {
+		linker := s.componentLinkManager.Get(componentId)
+		if !linker.EntityMask().Test(sourceId) {
+			continue
+		}
+		switch typedLinker := linker.(type) {
+		case api.InstanceLinker:
+			typedLinker.CloneInstance(sourceId, targetId)
+		case component.TagLinker:
+			typedLinker.Link(targetId)
+		}
+	}
+	return targetId, true
+}
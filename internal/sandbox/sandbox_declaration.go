@@ -0,0 +1,49 @@
+package sandbox
+
+import (
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+)
+
+// DeclarationType represents a typed query declaration type (declarations don't filter entities).
+type DeclarationType = uint8
+
+// Declaration types.
+const (
+	Optional = iota // Marks a typed query component as optional
+	Read            // Declares read-only access of a typed query to a component
+	Write           // Declares mutable access of a typed query to a component
+)
+
+// Declaration declares how a typed query accesses a component.
+type Declaration interface {
+	DeclarationType() DeclarationType
+	Registration() api.Registration
+	ComponentId() component.Id
+}
+
+// ComponentDeclaration is a typed query declaration for a component type.
+type ComponentDeclaration[T component.Component] struct {
+	ComponentRegistration[T]
+	declarationType DeclarationType
+}
+
+// NewComponentDeclaration creates a component declaration.
+func NewComponentDeclaration[T component.Component](declarationType DeclarationType) *ComponentDeclaration[T] {
+	return &ComponentDeclaration[T]{declarationType: declarationType}
+}
+
+// DeclarationType returns the declaration type.
+func (d *ComponentDeclaration[T]) DeclarationType() DeclarationType {
+	return d.declarationType
+}
+
+// ComponentId returns the component ID.
+func (d *ComponentDeclaration[T]) ComponentId() component.Id {
+	return d.GetLinker().ComponentId()
+}
+
+// Registration returns the component registration.
+func (d *ComponentDeclaration[T]) Registration() api.Registration {
+	return &d.ComponentRegistration
+}
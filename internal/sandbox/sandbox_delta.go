@@ -0,0 +1,45 @@
+package sandbox
+
+import (
+	"io"
+
+	internalSnapshot "github.com/andrei-cosmin/sandecs/internal/snapshot"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+)
+
+// Checkpoint records the current state as a delta baseline.
+func (s *Sandbox) Checkpoint() snapshot.Id {
+	return s.checkpoints.Record(s.entityLinker, s.componentLinkManager)
+}
+
+// ReleaseCheckpoint drops a delta baseline.
+func (s *Sandbox) ReleaseCheckpoint(id snapshot.Id) {
+	s.checkpoints.Release(id)
+}
+
+// SaveDelta writes the changes between the baseline checkpoint and the current state.
+func (s *Sandbox) SaveDelta(writer io.Writer, baseline snapshot.Id) error {
+	delta, err := s.checkpoints.Diff(baseline, s.entityLinker, s.componentLinkManager)
+	if err != nil {
+		return err
+	}
+	return internalSnapshot.EncodeDelta(writer, delta)
+}
+
+// ApplyDelta applies the delta read from the reader.
+func (s *Sandbox) ApplyDelta(reader io.Reader) error {
+	delta, err := internalSnapshot.DecodeDelta(reader, s.componentLinkManager, s.migrations)
+	if err != nil {
+		return err
+	}
+
+	// Removals are processed first, so that recycled entity ids can be linked again
+	delta.Unlink(s.entityLinker, s.componentLinkManager)
+	s.Update()
+
+	// Applying the delta completes a frame, so update hooks and validation see the replicated changes
+	delta.Link(s.entityLinker, s.componentLinkManager)
+	s.Update()
+	s.EndFrame()
+	return nil
+}
@@ -0,0 +1,140 @@
+package sandbox
+
+import (
+	"strings"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/inspect"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+)
+
+// Components describes all registered components and tags, ordered by component ID.
+func (s *Sandbox) Components() []inspect.Component {
+	size := s.componentLinkManager.Size()
+	descriptors := make([]inspect.Component, 0, size)
+	for componentId := range component.Id(size) {
+		descriptors = append(descriptors, describeLinker(s.componentLinkManager.Get(componentId)))
+	}
+	return descriptors
+}
+
+// EntityComponents describes the components and tags linked to an entity, ordered by component ID.
+func (s *Sandbox) EntityComponents(entityId entity.Id) []inspect.Component {
+	var descriptors []inspect.Component
+	if !s.IsEntityLinked(entityId) {
+		return descriptors
+	}
+	for componentId := range component.Id(s.componentLinkManager.Size()) {
+		linker := s.componentLinkManager.Get(componentId)
+		if linker.EntityMask().Test(entityId) {
+			descriptors = append(descriptors, describeLinker(linker))
+		}
+	}
+	return descriptors
+}
+
+// Entities returns the IDs of all linked entities, in ascending order.
+func (s *Sandbox) Entities() []entity.Id {
+	mask := s.entityLinker.EntityMask()
+	entityIds := make([]entity.Id, 0, mask.Count())
+	for entityId, hasNext := mask.NextSet(0); hasNext; entityId, hasNext = mask.NextSet(entityId + 1) {
+		entityIds = append(entityIds, entityId)
+	}
+	return entityIds
+}
+
+// ComponentValue returns a pointer to the component of an entity, or nil if not linked (or a tag).
+func (s *Sandbox) ComponentValue(entityId entity.Id, componentId component.Id) any {
+	if uint(componentId) >= s.componentLinkManager.Size() {
+		return nil
+	}
+	instanceLinker, ok := s.componentLinkManager.Get(componentId).(api.InstanceLinker)
+	if !ok {
+		return nil
+	}
+	return instanceLinker.GetInstance(entityId)
+}
+
+// Filters describes all registered filters, in registration order.
+func (s *Sandbox) Filters() []inspect.Filter {
+	caches := s.filterRegistry.Caches()
+	descriptors := make([]inspect.Filter, 0, len(caches))
+	for _, cache := range caches {
+		descriptor := s.describeFilter(cache)
+		descriptor.Views = cache.References()
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors
+}
+
+// Views describes the unreleased filter views, in creation order.
+func (s *Sandbox) Views() []inspect.View {
+	views := s.filterRegistry.Views()
+	descriptors := make([]inspect.View, 0, len(views))
+	for _, view := range views {
+		descriptors = append(descriptors, inspect.View{
+			Filter: s.describeFilter(view.Cache).Name,
+			Site:   view.Site,
+			Leaked: view.Leaked,
+		})
+	}
+	return descriptors
+}
+
+// describeFilter builds the descriptor of a filter cache.
+func (s *Sandbox) describeFilter(cache api.FilterCache) inspect.Filter {
+	descriptor := inspect.Filter{
+		Match:    s.componentNames(cache.RequiredComponentIds()),
+		Exclude:  s.componentNames(cache.ExcludedComponentIds()),
+		Union:    s.componentNames(cache.UnionComponentIds()),
+		Entities: cache.EntityMask().Count(),
+	}
+	descriptor.Name = describeRules(descriptor)
+	return descriptor
+}
+
+// describeLinker builds the descriptor of a component or tag linker.
+func describeLinker(linker api.ComponentLinker) inspect.Component {
+	descriptor := inspect.Component{
+		Id:     linker.ComponentId(),
+		Name:   linker.ComponentType(),
+		Kind:   inspect.TagKind,
+		Linked: linker.EntityMask().Count(),
+	}
+	if instanceLinker, ok := linker.(api.InstanceLinker); ok {
+		descriptor.Kind = inspect.ComponentKind
+		descriptor.Mode = instanceLinker.Mode()
+	}
+	if dynamicLinker, ok := linker.(api.DynamicLinker); ok {
+		descriptor.Fields = dynamicLinker.Schema().Fields()
+	}
+	return descriptor
+}
+
+// componentNames resolves component IDs to their type names.
+func (s *Sandbox) componentNames(componentIds []component.Id) []string {
+	names := make([]string, len(componentIds))
+	for index, componentId := range componentIds {
+		names[index] = s.componentLinkManager.Get(componentId).ComponentType()
+	}
+	return names
+}
+
+// describeRules summarizes filter rules, e.g. "Match(a, b) Exclude(c)".
+func describeRules(descriptor inspect.Filter) string {
+	var parts []string
+	for _, rule := range []struct {
+		name  string
+		names []string
+	}{
+		{"Match", descriptor.Match},
+		{"Exclude", descriptor.Exclude},
+		{"Union", descriptor.Union},
+	} {
+		if len(rule.names) > 0 {
+			parts = append(parts, rule.name+"("+strings.Join(rule.names, ", ")+")")
+		}
+	}
+	return strings.Join(parts, " ")
+}
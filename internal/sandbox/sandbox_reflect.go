@@ -0,0 +1,145 @@
+package sandbox
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+)
+
+// GetByName returns a pointer to the entity's component registered under the given name, or false if it isn't linked.
+func (s *Sandbox) GetByName(entityId entity.Id, name string) (any, bool) {
+	instanceLinker, err := s.instanceLinker(name)
+	if err != nil || !instanceLinker.EntityMask().Test(entityId) {
+		return nil, false
+	}
+	return instanceLinker.GetInstance(entityId), true
+}
+
+// SetField sets a field of the entity's component registered under the given name, and marks the component changed.
+// The path selects an exported field, with nested struct fields separated by dots (e.g. "Stats.Health").
+func (s *Sandbox) SetField(entityId entity.Id, name, path string, value any) error {
+	instanceLinker, err := s.instanceLinker(name)
+	if err != nil {
+		return err
+	}
+	if !s.IsEntityLinked(entityId) {
+		return &component.LinkError{Op: "set field", Component: name, Entity: entityId, Err: component.ErrEntityNotLinked}
+	}
+	if !instanceLinker.EntityMask().Test(entityId) {
+		return &component.LinkError{Op: "set field", Component: name, Entity: entityId, Err: component.ErrNotLinked}
+	}
+
+	instance := instanceLinker.GetInstance(entityId)
+	if record, ok := instance.(*component.Record); ok {
+		err = record.Set(path, value)
+	} else {
+		err = setField(reflect.ValueOf(instance).Elem(), name, path, value)
+	}
+	if err != nil {
+		return err
+	}
+	instanceLinker.MarkChanged(entityId)
+	return nil
+}
+
+// instanceLinker returns the linker of the component registered under the given name.
+func (s *Sandbox) instanceLinker(name string) (api.InstanceLinker, error) {
+	linker, ok := s.componentLinkManager.LookupComponent(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", component.ErrUnknownComponent, name)
+	}
+	return linker.(api.InstanceLinker), nil
+}
+
+// setField resolves the field path within the component value and assigns the value to it.
+func setField(target reflect.Value, name, path string, value any) error {
+	for _, fieldName := range strings.Split(path, ".") {
+		for target.Kind() == reflect.Pointer && !target.IsNil() {
+			target = target.Elem()
+		}
+		if target.Kind() != reflect.Struct {
+			return fmt.Errorf("%w: %s.%s", component.ErrUnknownField, name, path)
+		}
+		field, ok := target.Type().FieldByName(fieldName)
+		if !ok || !field.IsExported() {
+			return fmt.Errorf("%w: %s.%s", component.ErrUnknownField, name, path)
+		}
+		target = target.FieldByIndex(field.Index)
+	}
+
+	converted, ok := convert(reflect.ValueOf(value), target.Type())
+	if !ok {
+		return fmt.Errorf("%w: %s.%s is %s, got %T", component.ErrFieldKind, name, path, target.Type(), value)
+	}
+	target.Set(converted)
+	return nil
+}
+
+// convert returns the value as the given type: assignable values are used as is, numbers are converted if they fit the target range,
+// and nil is converted to the zero value of nillable types.
+func convert(value reflect.Value, targetType reflect.Type) (reflect.Value, bool) {
+	if !value.IsValid() {
+		switch targetType.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+			return reflect.Zero(targetType), true
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	if value.Type().AssignableTo(targetType) {
+		return value, true
+	}
+	if !isNumber(value.Kind()) || !isNumber(targetType.Kind()) {
+		return reflect.Value{}, false
+	}
+
+	// Numbers only convert if they fit the target type (no overflow, sign change or fractional part)
+	if !fits(value, targetType) {
+		return reflect.Value{}, false
+	}
+	return value.Convert(targetType), true
+}
+
+// fits returns true if the number is in the range of the target number type (integer targets also reject fractional values).
+func fits(value reflect.Value, targetType reflect.Type) bool {
+	bits := targetType.Bits()
+	switch {
+	case targetType.Kind() == reflect.Float64:
+		return true
+	case targetType.Kind() == reflect.Float32:
+		// Any integer fits a float32 (with rounding), finite floats must not overflow to infinity
+		return !value.CanFloat() || math.IsInf(value.Float(), 0) || math.IsNaN(value.Float()) || math.Abs(value.Float()) <= math.MaxFloat32
+	case targetType.Kind() <= reflect.Int64:
+		minimum, maximum := int64(math.MinInt64)>>(64-bits), int64(math.MaxInt64)>>(64-bits)
+		switch {
+		case value.CanInt():
+			return value.Int() >= minimum && value.Int() <= maximum
+		case value.CanUint():
+			return value.Uint() <= uint64(maximum)
+		default:
+			float := value.Float()
+			return float == math.Trunc(float) && float >= -math.Ldexp(1, bits-1) && float < math.Ldexp(1, bits-1)
+		}
+	default:
+		maximum := uint64(math.MaxUint64) >> (64 - bits)
+		switch {
+		case value.CanInt():
+			return value.Int() >= 0 && uint64(value.Int()) <= maximum
+		case value.CanUint():
+			return value.Uint() <= maximum
+		default:
+			float := value.Float()
+			return float == math.Trunc(float) && float >= 0 && float < math.Ldexp(1, bits)
+		}
+	}
+}
+
+// isNumber returns true for integer and floating point kinds.
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
@@ -21,6 +21,27 @@ func (r *ComponentRegistration[T]) GetLinker() component.Linker[T] {
 	return r.linker
 }
 
+// NameRegistration registers component type T under an explicit name.
+type NameRegistration[T component.Component] struct {
+	name string
+	err  error
+}
+
+// NewNameRegistration creates a component name registration.
+func NewNameRegistration[T component.Component](name string) *NameRegistration[T] {
+	return &NameRegistration[T]{name: name}
+}
+
+// Execute registers the component under the name, and stores the name conflict if any.
+func (r *NameRegistration[T]) Execute(context api.ComponentLinkManager) {
+	r.err = internalComponent.RegisterComponentName[T](r.name, context)
+}
+
+// Err returns the registration error.
+func (r *NameRegistration[T]) Err() error {
+	return r.err
+}
+
 // TagRegistration holds the linker for a tag.
 type TagRegistration struct {
 	tag    component.Tag
@@ -41,3 +62,43 @@ func (r *TagRegistration) Execute(context api.ComponentLinkManager) {
 func (r *TagRegistration) GetLinker() component.TagLinker {
 	return r.linker
 }
+
+// TypedTagRegistration holds the linker for the typed tag T.
+type TypedTagRegistration[T component.Component] struct {
+	linker component.TagLinker
+}
+
+// Execute registers the typed tag and stores the linker.
+func (r *TypedTagRegistration[T]) Execute(context api.ComponentLinkManager) {
+	r.linker = internalComponent.RegisterTypedTagLinker[T](context).(component.TagLinker)
+}
+
+// GetLinker returns the linker for the typed tag T.
+func (r *TypedTagRegistration[T]) GetLinker() component.TagLinker {
+	return r.linker
+}
+
+// DynamicRegistration holds the linker for a dynamic component.
+type DynamicRegistration struct {
+	schema *component.Schema
+	linker component.DynamicLinker
+	err    error
+}
+
+// NewDynamicRegistration creates a dynamic component registration.
+func NewDynamicRegistration(schema *component.Schema) *DynamicRegistration {
+	return &DynamicRegistration{schema: schema}
+}
+
+// Execute registers the dynamic component and stores the linker, or the schema conflict.
+func (r *DynamicRegistration) Execute(context api.ComponentLinkManager) {
+	var linker api.ComponentLinker
+	if linker, r.err = internalComponent.RegisterDynamicLinker(r.schema, context); r.err == nil {
+		r.linker = linker.(component.DynamicLinker)
+	}
+}
+
+// GetLinker returns the linker for the dynamic component, or the registration error.
+func (r *DynamicRegistration) GetLinker() (component.DynamicLinker, error) {
+	return r.linker, r.err
+}
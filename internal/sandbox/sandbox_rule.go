@@ -1,6 +1,8 @@
 package sandbox
 
 import (
+	"fmt"
+
 	"github.com/andrei-cosmin/sandecs/component"
 	"github.com/andrei-cosmin/sandecs/internal/api"
 )
@@ -78,3 +80,70 @@ func (r *TagRule) ComponentId() component.Id {
 func (r *TagRule) Registration() api.Registration {
 	return &r.TagRegistration
 }
+
+// TypedTagRule is a filter rule for a typed tag.
+type TypedTagRule[T component.Component] struct {
+	TypedTagRegistration[T]
+	ruleType Type
+}
+
+// NewTypedTagRule creates a typed tag rule.
+func NewTypedTagRule[T component.Component](ruleType Type) *TypedTagRule[T] {
+	return &TypedTagRule[T]{ruleType: ruleType}
+}
+
+// RuleType returns the rule type.
+func (r *TypedTagRule[T]) RuleType() Type {
+	return r.ruleType
+}
+
+// ComponentId returns the tag's component ID.
+func (r *TypedTagRule[T]) ComponentId() component.Id {
+	return r.GetLinker().ComponentId()
+}
+
+// Registration returns the typed tag registration.
+func (r *TypedTagRule[T]) Registration() api.Registration {
+	return &r.TypedTagRegistration
+}
+
+// IdRule is a filter rule for a registered component ID (e.g. a dynamic component).
+type IdRule struct {
+	IdRegistration
+	ruleType Type
+}
+
+// NewIdRule creates a component ID rule.
+func NewIdRule(componentId component.Id, ruleType Type) *IdRule {
+	return &IdRule{
+		IdRegistration: IdRegistration{componentId: componentId},
+		ruleType:       ruleType,
+	}
+}
+
+// RuleType returns the rule type.
+func (r *IdRule) RuleType() Type {
+	return r.ruleType
+}
+
+// ComponentId returns the component ID.
+func (r *IdRule) ComponentId() component.Id {
+	return r.componentId
+}
+
+// Registration returns the component ID registration.
+func (r *IdRule) Registration() api.Registration {
+	return &r.IdRegistration
+}
+
+// IdRegistration checks that a component ID is registered (the component itself is registered by its linker).
+type IdRegistration struct {
+	componentId component.Id
+}
+
+// Execute panics with component.ErrUnknownComponent if the component ID isn't registered.
+func (r *IdRegistration) Execute(context api.ComponentLinkManager) {
+	if r.componentId >= context.Size() {
+		panic(fmt.Errorf("%w: %d", component.ErrUnknownComponent, r.componentId))
+	}
+}
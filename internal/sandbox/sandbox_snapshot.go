@@ -0,0 +1,62 @@
+package sandbox
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	internalSnapshot "github.com/andrei-cosmin/sandecs/internal/snapshot"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+)
+
+// Save writes the entities, components and tags of the sandbox.
+func (s *Sandbox) Save(writer io.Writer, format snapshot.Format) error {
+	return internalSnapshot.Encode(writer, internalSnapshot.Capture(s.entityLinker, s.componentLinkManager), format)
+}
+
+// Load replaces the contents of the sandbox with the snapshot read from the reader.
+func (s *Sandbox) Load(reader io.Reader) error {
+	data, err := internalSnapshot.Decode(reader, s.componentLinkManager, s.migrations)
+	if err != nil {
+		return err
+	}
+
+	// Remove the current entities (and their components), before restoring the snapshot ids
+	entityMask := s.entityLinker.EntityMask()
+	for entityId, hasNext := entityMask.NextSet(0); hasNext; entityId, hasNext = entityMask.NextSet(entityId + 1) {
+		s.entityLinker.Unlink(entityId)
+	}
+	s.Update()
+
+	// The reload completes a frame, so update hooks (indexes, observers) and validation see the restored contents
+	internalSnapshot.Restore(data, s.entityLinker, s.componentLinkManager)
+	s.Update()
+	s.EndFrame()
+	return nil
+}
+
+// Import links the snapshot read from the reader with newly allocated entity IDs, returning the old → new ID map.
+func (s *Sandbox) Import(reader io.Reader) (map[entity.Id]entity.Id, error) {
+	data, err := internalSnapshot.Decode(reader, s.componentLinkManager, s.migrations)
+	if err != nil {
+		return nil, err
+	}
+	return internalSnapshot.Import(data, s.entityLinker, s.componentLinkManager), nil
+}
+
+// RegisterMigration registers a conversion for binary snapshots that stored component T with the layout of Old.
+func RegisterMigration[Old any, T component.Component](s *Sandbox, migrate func(old *Old, current *T)) {
+	registration := ComponentRegistration[T]{}
+	s.Accept(&registration)
+	componentType := registration.GetLinker().(api.ComponentLinker).ComponentType()
+	s.migrations.Register(componentType, internalSnapshot.LayoutHash(reflect.TypeFor[Old]()), internalSnapshot.Migration{
+		NewInstance: func() any {
+			return new(Old)
+		},
+		Migrate: func(old, current any) {
+			migrate(old.(*Old), current.(*T))
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package sandbox
+
+import (
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+)
+
+// SpawnComponent holds a component value to be copied into spawned entities.
+type SpawnComponent struct {
+	linker func(s *Sandbox) api.InstanceLinker
+	value  any
+}
+
+// NewSpawnComponent creates a spawn component for type T with the given value.
+func NewSpawnComponent[T component.Component](value T) SpawnComponent {
+	return SpawnComponent{
+		linker: func(s *Sandbox) api.InstanceLinker {
+			registration := ComponentRegistration[T]{}
+			s.Accept(&registration)
+			return registration.GetLinker().(api.InstanceLinker)
+		},
+		value: &value,
+	}
+}
+
+// Spawn links count entities with the given components and tags, linking each component and tag in one batch.
+func (s *Sandbox) Spawn(count uint, components []SpawnComponent, tags []component.Tag) []entity.Id {
+	entityIds := s.entityLinker.LinkN(count)
+	for _, spawnComponent := range components {
+		spawnComponent.linker(s).SetInstances(entityIds, spawnComponent.value)
+	}
+	for _, tag := range tags {
+		registration := NewTagRegistration(tag)
+		s.Accept(registration)
+		registration.GetLinker().(api.BatchLinker).LinkBatch(entityIds)
+	}
+	return entityIds
+}
@@ -0,0 +1,91 @@
+package sandbox
+
+import (
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/inspect"
+)
+
+// frameStats accumulates the statistics of the current frame (a frame may span several updates, e.g. Load).
+type frameStats struct {
+	frame             uint64
+	entitiesUnlinked  uint
+	componentUnlinks  []uint
+	filtersRecomputed uint
+	filtersChanged    uint
+	phases            inspect.Phases
+	entityCount       uint   // Linked entities at the end of the previous frame
+	componentCounts   []uint // Linked entities per component at the end of the previous frame
+}
+
+// recordUnlinks accumulates the unlinked entity counts per component ID.
+func (f *frameStats) recordUnlinks(unlinkCounts []uint) {
+	f.componentUnlinks = growCounts(f.componentUnlinks, len(unlinkCounts))
+	for componentId, count := range unlinkCounts {
+		f.componentUnlinks[componentId] += count
+	}
+}
+
+// growCounts extends the counts with zeros up to the given size (components registered during the frame).
+func growCounts(counts []uint, size int) []uint {
+	for len(counts) < size {
+		counts = append(counts, 0)
+	}
+	return counts
+}
+
+// Stats returns the statistics of the last frame.
+func (s *Sandbox) Stats() inspect.Stats {
+	return s.stats
+}
+
+// Instrument registers an instrumentation that receives the statistics of each frame.
+func (s *Sandbox) Instrument(instrumentation inspect.Instrumentation) {
+	s.instrumentations = append(s.instrumentations, instrumentation)
+}
+
+// EndFrame validates the sandbox (if enabled) and completes the frame statistics, then notifies the instrumentations and update hooks.
+func (s *Sandbox) EndFrame() {
+	s.validateFrame()
+
+	current := &s.frameStats
+	current.frame++
+
+	// Links are immediate, so they are derived from the counts at the frame boundaries
+	entityCount := s.entityLinker.EntityMask().Count()
+	stats := inspect.Stats{
+		Frame:             current.frame,
+		EntitiesLinked:    entityCount + current.entitiesUnlinked - current.entityCount,
+		EntitiesUnlinked:  current.entitiesUnlinked,
+		Components:        make([]inspect.ComponentStats, s.componentLinkManager.Size()),
+		FiltersRecomputed: current.filtersRecomputed,
+		FiltersChanged:    current.filtersChanged,
+		Phases:            current.phases,
+	}
+	current.componentUnlinks = growCounts(current.componentUnlinks, len(stats.Components))
+	current.componentCounts = growCounts(current.componentCounts, len(stats.Components))
+	for componentId := range component.Id(len(stats.Components)) {
+		linker := s.componentLinkManager.Get(componentId)
+		linkedCount := linker.EntityMask().Count()
+		stats.Components[componentId] = inspect.ComponentStats{
+			Id:       componentId,
+			Name:     linker.ComponentType(),
+			Linked:   linkedCount + current.componentUnlinks[componentId] - current.componentCounts[componentId],
+			Unlinked: current.componentUnlinks[componentId],
+		}
+		current.componentCounts[componentId] = linkedCount
+		current.componentUnlinks[componentId] = 0
+	}
+
+	// Reset the accumulators for the next frame
+	current.entityCount = entityCount
+	current.entitiesUnlinked = 0
+	current.filtersRecomputed = 0
+	current.filtersChanged = 0
+	current.phases = inspect.Phases{}
+	s.stats = stats
+
+	for _, instrumentation := range s.instrumentations {
+		instrumentation.RecordFrame(stats)
+	}
+	s.runUpdateHooks()
+}
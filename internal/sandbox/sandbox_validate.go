@@ -0,0 +1,44 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/inspect"
+	"github.com/andrei-cosmin/sandecs/options"
+)
+
+// Validate checks the sandbox invariants: entity removals, component links and storage, and filter caches.
+// Filter caches are only checked if there are no pending changes (they are refreshed by Update).
+func (s *Sandbox) Validate() error {
+	var errs []error
+	if count := s.entityLinker.EntityMask().DifferenceCardinality(s.entityLinker.GetScheduledRemoves().Clone()); count > 0 {
+		errs = append(errs, fmt.Errorf("entities: %d scheduled removals aren't linked", count))
+	}
+	for componentId := range component.Id(s.componentLinkManager.Size()) {
+		linker := s.componentLinkManager.Get(componentId)
+		if err := linker.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", linker.ComponentType(), err))
+		}
+	}
+	if s.IsUpdated() {
+		if err := s.filterRegistry.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", inspect.ErrInconsistent, errors.Join(errs...))
+	}
+	return nil
+}
+
+// validateFrame validates the sandbox at the end of the frame if enabled (see options.Validate).
+func (s *Sandbox) validateFrame() {
+	if s.flags&options.Validate == 0 {
+		return
+	}
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,340 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// binaryMagic identifies binary snapshots.
+var binaryMagic = []byte("SDCS")
+
+// binaryVersion is the version of the binary layout written by EncodeBinary.
+const binaryVersion uint16 = 1
+
+// maxEntityId bounds the entity IDs read from snapshots (IDs size the decoded bitsets, not the input).
+const maxEntityId = 1 << 28
+
+// EncodeBinary writes the snapshot in the binary format:
+//   - header: magic, version and schema (component labels with their layout hashes, tag labels)
+//   - the entity bitset
+//   - one column per component: the entity bitset followed by the length-prefixed gob encoded values
+//   - one bitset per tag
+//
+// Components are encoded with encoding/gob, so types can customize their encoding by implementing gob.GobEncoder.
+func EncodeBinary(writer io.Writer, data *Snapshot) error {
+	stream := &binaryWriter{writer: bufio.NewWriter(writer)}
+
+	// Header
+	stream.write(binaryMagic)
+	stream.writeValue(binaryVersion)
+	stream.writeUvarint(uint64(len(data.Components)))
+	for _, column := range data.Components {
+		stream.writeString(column.Name)
+		stream.writeValue(column.Layout)
+	}
+	stream.writeUvarint(uint64(len(data.Tags)))
+	for _, column := range data.Tags {
+		stream.writeString(column.Name)
+	}
+
+	// Entities and component columns
+	stream.writeMask(data.Entities)
+	for _, column := range data.Components {
+		stream.writeMask(column.Entities)
+		encoded, err := encodeColumn(&column)
+		if err != nil {
+			return err
+		}
+		stream.writeUvarint(uint64(len(encoded)))
+		stream.write(encoded)
+	}
+
+	// Tags
+	for _, column := range data.Tags {
+		stream.writeMask(column.Entities)
+	}
+
+	if stream.err != nil {
+		return stream.err
+	}
+	return stream.writer.Flush()
+}
+
+// DecodeBinary reads a binary snapshot, decoding component values into the types registered in the link manager.
+// Columns saved with a different layout are converted with the matching migration.
+func DecodeBinary(reader io.Reader, componentLinkManager api.ComponentLinkManager, migrations *Migrations) (*Snapshot, error) {
+	stream := newBinaryReader(reader)
+
+	// Header
+	magic := make([]byte, len(binaryMagic))
+	stream.read(magic)
+	if stream.err == nil && !bytes.Equal(magic, binaryMagic) {
+		return nil, snapshot.ErrUnknownFormat
+	}
+	var version uint16
+	stream.readValue(&version)
+	if stream.err == nil && (version == 0 || version > binaryVersion) {
+		return nil, fmt.Errorf("%w: unsupported version %d", snapshot.ErrInvalidSnapshot, version)
+	}
+
+	// Counts are bounded by the remaining input, and slices grow as columns are actually decoded
+	var components []Column
+	for count := stream.readLength(); count > 0 && stream.err == nil; count-- {
+		column := Column{Name: stream.readString()}
+		stream.readValue(&column.Layout)
+		components = append(components, column)
+	}
+	var tags []Column
+	for count := stream.readLength(); count > 0 && stream.err == nil; count-- {
+		tags = append(tags, Column{Name: stream.readString()})
+	}
+
+	data := &Snapshot{
+		Entities:   stream.readMask(),
+		Components: components,
+		Tags:       tags,
+	}
+	if stream.err != nil {
+		return nil, stream.failure()
+	}
+
+	// Component columns
+	for index := range data.Components {
+		column := &data.Components[index]
+		column.Entities = stream.readMask()
+		encoded := stream.readBytes()
+		if stream.err != nil {
+			return nil, stream.failure()
+		}
+		if err := decodeColumn(column, encoded, componentLinkManager, migrations); err != nil {
+			return nil, err
+		}
+	}
+
+	// Tags
+	for index := range data.Tags {
+		data.Tags[index].Entities = stream.readMask()
+	}
+	if stream.err != nil {
+		return nil, stream.failure()
+	}
+
+	if err := validate(data, componentLinkManager); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// encodeColumn encodes the values of the column with encoding/gob.
+func encodeColumn(column *Column) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := gob.NewEncoder(&buffer)
+	for _, value := range column.Values {
+		if err := encoder.Encode(value); err != nil {
+			return nil, fmt.Errorf("snapshot: encoding %q: %w", column.Name, err)
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// decodeColumn decodes the gob encoded values of the column, migrating them if the layout changed.
+func decodeColumn(column *Column, encoded []byte, componentLinkManager api.ComponentLinkManager, migrations *Migrations) error {
+	linker, ok := componentLinkManager.LookupComponent(column.Name)
+	if !ok {
+		return fmt.Errorf("%w: %q", snapshot.ErrUnknownComponent, column.Name)
+	}
+	instanceLinker, ok := linker.(api.InstanceLinker)
+	if !ok {
+		return fmt.Errorf("%w: %q is not a component", snapshot.ErrInvalidSnapshot, column.Name)
+	}
+
+	// Decode directly into the current type, unless the saved layout differs
+	var migration *Migration
+	if column.Layout != layoutOf(instanceLinker) {
+		found, ok := migrations.find(column.Name, column.Layout)
+		if !ok {
+			return fmt.Errorf("%w: %q has no migration for layout %x", snapshot.ErrSchemaMismatch, column.Name, column.Layout)
+		}
+		migration = &found
+	}
+
+	decoder := gob.NewDecoder(bytes.NewReader(encoded))
+	column.Values = make([]any, column.Entities.Count())
+	for index := range column.Values {
+		instance := instanceLinker.NewInstance()
+		if migration != nil {
+			instance = migration.NewInstance()
+		}
+		if err := decoder.Decode(instance); err != nil {
+			return fmt.Errorf("%w: decoding %q: %w", snapshot.ErrInvalidSnapshot, column.Name, err)
+		}
+		if migration != nil {
+			current := instanceLinker.NewInstance()
+			migration.Migrate(instance, current)
+			instance = current
+		}
+		column.Values[index] = instance
+	}
+	return nil
+}
+
+// binaryWriter writes binary values, keeping the first error.
+type binaryWriter struct {
+	writer *bufio.Writer
+	err    error
+}
+
+func (w *binaryWriter) write(data []byte) {
+	if w.err == nil {
+		_, w.err = w.writer.Write(data)
+	}
+}
+
+func (w *binaryWriter) writeValue(value any) {
+	if w.err == nil {
+		w.err = binary.Write(w.writer, binary.LittleEndian, value)
+	}
+}
+
+func (w *binaryWriter) writeUvarint(value uint64) {
+	w.write(binary.AppendUvarint(nil, value))
+}
+
+func (w *binaryWriter) writeString(value string) {
+	w.writeUvarint(uint64(len(value)))
+	w.write([]byte(value))
+}
+
+// writeMask writes the mask straight from its bitset (a copy is only made for other mask implementations).
+func (w *binaryWriter) writeMask(mask bit.Mask) {
+	if w.err != nil {
+		return
+	}
+	if bitMask, ok := mask.(*bit.BitMask); ok {
+		_, w.err = bitMask.Bits().WriteTo(w.writer)
+	} else {
+		_, w.err = mask.Clone().WriteTo(w.writer)
+	}
+}
+
+// writeSparse writes the set bits as a count followed by the gaps between them (compact for sparse masks).
+func (w *binaryWriter) writeSparse(mask bit.Mask) {
+	w.writeUvarint(uint64(mask.Count()))
+	previous := entity.Id(0)
+	forEach(mask, func(entityId entity.Id, _ int) {
+		w.writeUvarint(uint64(entityId - previous))
+		previous = entityId
+	})
+}
+
+// binaryReader reads binary values, keeping the first error.
+//
+// The input is read in memory, so lengths can be checked against the remaining bytes before allocating:
+// corrupted or malicious lengths fail with io.ErrUnexpectedEOF instead of exhausting memory.
+type binaryReader struct {
+	reader *bytes.Reader
+	err    error
+}
+
+func newBinaryReader(reader io.Reader) *binaryReader {
+	data, err := io.ReadAll(reader)
+	return &binaryReader{reader: bytes.NewReader(data), err: err}
+}
+
+func (r *binaryReader) read(data []byte) {
+	if r.err == nil {
+		_, r.err = io.ReadFull(r.reader, data)
+	}
+}
+
+func (r *binaryReader) readValue(value any) {
+	if r.err == nil {
+		r.err = binary.Read(r.reader, binary.LittleEndian, value)
+	}
+}
+
+// readMaskLength reads the length header of a bitset (in the bitset byte order).
+func (r *binaryReader) readMaskLength() uint64 {
+	var length uint64
+	if r.err == nil {
+		r.err = binary.Read(r.reader, bitset.BinaryOrder(), &length)
+	}
+	return length
+}
+
+func (r *binaryReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	var value uint64
+	value, r.err = binary.ReadUvarint(r.reader)
+	return value
+}
+
+// readLength reads a length or a count, which can't exceed the remaining input (elements take at least one byte).
+func (r *binaryReader) readLength() uint64 {
+	length := r.readUvarint()
+	if r.err == nil && length > uint64(r.reader.Len()) {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	return length
+}
+
+func (r *binaryReader) readBytes() []byte {
+	data := make([]byte, r.readLength())
+	r.read(data)
+	return data
+}
+
+func (r *binaryReader) readString() string {
+	return string(r.readBytes())
+}
+
+// readSparse reads a mask written by writeSparse.
+func (r *binaryReader) readSparse() bit.Mask {
+	entities := bitset.New(0)
+	count := r.readLength()
+	entityId := uint64(0)
+	for range count {
+		entityId += r.readUvarint()
+		if r.err != nil || entityId > maxEntityId {
+			r.err = cmp.Or(r.err, io.ErrUnexpectedEOF)
+			break
+		}
+		entities.Set(uint(entityId))
+	}
+	return bit.NewMask(entities)
+}
+
+// readMask reads a bitset written by writeMask, checking its length against the remaining input first.
+func (r *binaryReader) readMask() bit.Mask {
+	entities := bitset.New(0)
+	length := r.readMaskLength()
+	if r.err != nil {
+		return bit.NewMask(entities)
+	}
+	if words := length/64 + min(length%64, 1); length > maxEntityId || words*8 > uint64(r.reader.Len()) {
+		r.err = io.ErrUnexpectedEOF
+		return bit.NewMask(entities)
+	}
+	_, _ = r.reader.Seek(-8, io.SeekCurrent)
+	_, r.err = entities.ReadFrom(r.reader)
+	return bit.NewMask(entities)
+}
+
+// failure wraps the read error as an invalid snapshot.
+func (r *binaryReader) failure() error {
+	return fmt.Errorf("%w: %w", snapshot.ErrInvalidSnapshot, r.err)
+}
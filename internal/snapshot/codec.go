@@ -0,0 +1,44 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+)
+
+// Encode writes the snapshot using the given format.
+func Encode(writer io.Writer, data *Snapshot, format snapshot.Format) error {
+	switch format {
+	case snapshot.JSON:
+		return EncodeJSON(writer, data)
+	case snapshot.Binary:
+		return EncodeBinary(writer, data)
+	default:
+		return snapshot.ErrUnknownFormat
+	}
+}
+
+// Decode reads a snapshot, detecting its format from the leading bytes.
+func Decode(reader io.Reader, componentLinkManager api.ComponentLinkManager, migrations *Migrations) (*Snapshot, error) {
+	bufferedReader := bufio.NewReader(reader)
+	if leading, err := bufferedReader.Peek(len(binaryMagic)); err == nil && bytes.Equal(leading, binaryMagic) {
+		return DecodeBinary(bufferedReader, componentLinkManager, migrations)
+	}
+	for {
+		leading, err := bufferedReader.Peek(1)
+		if err != nil {
+			return nil, snapshot.ErrUnknownFormat
+		}
+		switch leading[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = bufferedReader.ReadByte()
+		case '{':
+			return DecodeJSON(bufferedReader, componentLinkManager)
+		default:
+			return nil, snapshot.ErrUnknownFormat
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	internalComponent "github.com/andrei-cosmin/sandecs/internal/component"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// deltaMagic identifies binary deltas.
+var deltaMagic = []byte("SDCD")
+
+// deltaVersion is the version of the delta layout written by EncodeDelta.
+const deltaVersion uint16 = 1
+
+// Delta holds the changes between a baseline and the current state of a sandbox.
+type Delta struct {
+	Created    bit.Mask
+	Removed    bit.Mask
+	Components []ColumnDelta
+	Tags       []ColumnDelta
+}
+
+// ColumnDelta holds the linked (or changed) entities of a component or tag, along with the unlinked entities.
+// Unlinks caused by removed entities are not included.
+type ColumnDelta struct {
+	Column
+	Unlinked bit.Mask
+}
+
+// checkpoint records the linked entities of a sandbox (and its components) at a given tick.
+type checkpoint struct {
+	tick     uint64
+	entities *bitset.BitSet
+	linked   map[string]*bitset.BitSet
+}
+
+// Checkpoints holds the recorded delta baselines.
+type Checkpoints struct {
+	cursor      snapshot.Id
+	checkpoints map[snapshot.Id]*checkpoint
+}
+
+// NewCheckpoints creates an empty checkpoint registry.
+func NewCheckpoints() *Checkpoints {
+	return &Checkpoints{checkpoints: make(map[snapshot.Id]*checkpoint)}
+}
+
+// Record stores the linked entities of the sandbox and its components, returning the checkpoint id.
+func (c *Checkpoints) Record(entityLinker entity.MaskView, componentLinkManager api.ComponentLinkManager) snapshot.Id {
+	recorded := &checkpoint{
+		tick:     componentLinkManager.Tick(),
+		entities: entityLinker.EntityMask().Clone(),
+		linked:   make(map[string]*bitset.BitSet),
+	}
+	for componentId := range component.Id(componentLinkManager.Size()) {
+		linker := componentLinkManager.Get(componentId)
+		recorded.linked[linker.ComponentType()] = linker.EntityMask().Clone()
+	}
+
+	c.cursor++
+	c.checkpoints[c.cursor] = recorded
+	return c.cursor
+}
+
+// Release drops the checkpoint.
+func (c *Checkpoints) Release(id snapshot.Id) {
+	delete(c.checkpoints, id)
+}
+
+// Diff computes the changes between the baseline checkpoint and the current state of the sandbox.
+//
+// Component values are included if linked or marked changed since the baseline. Values are referenced, not copied.
+func (c *Checkpoints) Diff(baseline snapshot.Id, entityLinker entity.MaskView, componentLinkManager api.ComponentLinkManager) (*Delta, error) {
+	recorded := &checkpoint{entities: bitset.New(0), linked: make(map[string]*bitset.BitSet)}
+	if baseline != snapshot.None {
+		var ok bool
+		if recorded, ok = c.checkpoints[baseline]; !ok {
+			return nil, fmt.Errorf("%w: %d", snapshot.ErrUnknownBaseline, baseline)
+		}
+	}
+
+	currentEntities := entityLinker.EntityMask()
+	created := currentEntities.Clone()
+	created.InPlaceDifference(recorded.entities)
+	removed := recorded.entities.Clone()
+	currentEntities.Difference(removed)
+
+	delta := &Delta{
+		Created:    bit.NewMask(created),
+		Removed:    bit.NewMask(removed),
+		Components: make([]ColumnDelta, 0),
+		Tags:       make([]ColumnDelta, 0),
+	}
+
+	for componentId := range component.Id(componentLinkManager.Size()) {
+		linker := componentLinkManager.Get(componentId)
+		current := linker.EntityMask()
+		base, ok := recorded.linked[linker.ComponentType()]
+		if !ok {
+			base = bitset.New(0)
+		}
+
+		unlinked := base.Clone()
+		current.Difference(unlinked)
+		unlinked.InPlaceDifference(removed)
+
+		columnDelta := ColumnDelta{Column: Column{Name: linker.ComponentType()}, Unlinked: bit.NewMask(unlinked)}
+		instanceLinker, isComponent := linker.(api.InstanceLinker)
+		if isComponent {
+			changed := bitset.New(0)
+			forEach(current, func(entityId entity.Id, _ int) {
+				if !base.Test(entityId) || linker.ChangedSince(entityId, recorded.tick) {
+					changed.Set(entityId)
+					columnDelta.Values = append(columnDelta.Values, instanceLinker.GetInstance(entityId))
+				}
+			})
+			columnDelta.Entities = bit.NewMask(changed)
+			columnDelta.Layout = layoutOf(instanceLinker)
+		} else {
+			linked := current.Clone()
+			linked.InPlaceDifference(base)
+			columnDelta.Entities = bit.NewMask(linked)
+		}
+
+		if columnDelta.Entities.None() && unlinked.None() {
+			continue
+		}
+		if isComponent {
+			delta.Components = append(delta.Components, columnDelta)
+		} else {
+			delta.Tags = append(delta.Tags, columnDelta)
+		}
+	}
+
+	return delta, nil
+}
+
+// Unlink applies the removals of the delta (entities, components and tags).
+func (d *Delta) Unlink(entityLinker api.EntityLinker, componentLinkManager api.ComponentLinkManager) {
+	forEach(d.Removed, func(entityId entity.Id, _ int) {
+		entityLinker.Unlink(entityId)
+	})
+	for _, columns := range []struct {
+		deltas []ColumnDelta
+		lookup func(string) (api.ComponentLinker, bool)
+	}{
+		{d.Components, componentLinkManager.LookupComponent},
+		{d.Tags, componentLinkManager.LookupTag},
+	} {
+		for _, columnDelta := range columns.deltas {
+			linker, ok := columns.lookup(columnDelta.Name)
+			if !ok {
+				continue
+			}
+			forEach(columnDelta.Unlinked, func(entityId entity.Id, _ int) {
+				_ = linker.TryUnlink(entityId)
+			})
+		}
+	}
+}
+
+// Link applies the additions and changes of the delta (entities, components and tags).
+// Tags missing from the sandbox are registered.
+func (d *Delta) Link(entityLinker api.EntityLinker, componentLinkManager api.ComponentLinkManager) {
+	forEach(d.Created, func(entityId entity.Id, _ int) {
+		entityLinker.LinkId(entityId)
+	})
+
+	for _, columnDelta := range d.Components {
+		linker, _ := componentLinkManager.LookupComponent(columnDelta.Name)
+		instanceLinker := linker.(api.InstanceLinker)
+		forEach(columnDelta.Entities, func(entityId entity.Id, index int) {
+			instanceLinker.SetInstance(entityId, columnDelta.Values[index])
+		})
+	}
+
+	for _, columnDelta := range d.Tags {
+		tagLinker := internalComponent.RegisterTagLinker(columnDelta.Name, componentLinkManager).(api.BatchLinker)
+		tagLinker.LinkBatch(entityIds(columnDelta.Entities, nil))
+	}
+}
+
+// EncodeDelta writes the delta in a binary layout (masks are written as sparse entity lists).
+func EncodeDelta(writer io.Writer, delta *Delta) error {
+	stream := &binaryWriter{writer: bufio.NewWriter(writer)}
+	stream.write(deltaMagic)
+	stream.writeValue(deltaVersion)
+	stream.writeSparse(delta.Created)
+	stream.writeSparse(delta.Removed)
+
+	stream.writeUvarint(uint64(len(delta.Components)))
+	for _, columnDelta := range delta.Components {
+		stream.writeString(columnDelta.Name)
+		stream.writeValue(columnDelta.Layout)
+		stream.writeSparse(columnDelta.Unlinked)
+		stream.writeSparse(columnDelta.Entities)
+		encoded, err := encodeColumn(&columnDelta.Column)
+		if err != nil {
+			return err
+		}
+		stream.writeUvarint(uint64(len(encoded)))
+		stream.write(encoded)
+	}
+
+	stream.writeUvarint(uint64(len(delta.Tags)))
+	for _, columnDelta := range delta.Tags {
+		stream.writeString(columnDelta.Name)
+		stream.writeSparse(columnDelta.Unlinked)
+		stream.writeSparse(columnDelta.Entities)
+	}
+
+	if stream.err != nil {
+		return stream.err
+	}
+	return stream.writer.Flush()
+}
+
+// DecodeDelta reads a delta, decoding component values into the types registered in the link manager.
+func DecodeDelta(reader io.Reader, componentLinkManager api.ComponentLinkManager, migrations *Migrations) (*Delta, error) {
+	stream := newBinaryReader(reader)
+
+	magic := make([]byte, len(deltaMagic))
+	stream.read(magic)
+	if stream.err == nil && !bytes.Equal(magic, deltaMagic) {
+		return nil, snapshot.ErrUnknownFormat
+	}
+	var version uint16
+	stream.readValue(&version)
+	if stream.err == nil && (version == 0 || version > deltaVersion) {
+		return nil, fmt.Errorf("%w: unsupported version %d", snapshot.ErrInvalidSnapshot, version)
+	}
+
+	delta := &Delta{
+		Created: stream.readSparse(),
+		Removed: stream.readSparse(),
+	}
+
+	// Counts are bounded by the remaining input, and slices grow as columns are actually decoded
+	for count := stream.readLength(); count > 0 && stream.err == nil; count-- {
+		columnDelta := ColumnDelta{Column: Column{Name: stream.readString()}}
+		stream.readValue(&columnDelta.Layout)
+		columnDelta.Unlinked = stream.readSparse()
+		columnDelta.Entities = stream.readSparse()
+		encoded := stream.readBytes()
+		if stream.err != nil {
+			return nil, stream.failure()
+		}
+		if err := decodeColumn(&columnDelta.Column, encoded, componentLinkManager, migrations); err != nil {
+			return nil, err
+		}
+		delta.Components = append(delta.Components, columnDelta)
+	}
+
+	for count := stream.readLength(); count > 0 && stream.err == nil; count-- {
+		columnDelta := ColumnDelta{Column: Column{Name: stream.readString()}}
+		columnDelta.Unlinked = stream.readSparse()
+		columnDelta.Entities = stream.readSparse()
+		delta.Tags = append(delta.Tags, columnDelta)
+	}
+
+	if stream.err != nil {
+		return nil, stream.failure()
+	}
+	for _, columnDelta := range delta.Components {
+		if err := validateComponent(columnDelta.Column, componentLinkManager); err != nil {
+			return nil, err
+		}
+	}
+	return delta, nil
+}
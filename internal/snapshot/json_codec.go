@@ -0,0 +1,160 @@
+package snapshot
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/bits-and-blooms/bitset"
+)
+
+// jsonVersion is the version written in JSON snapshots.
+const jsonVersion = 1
+
+// jsonSnapshot is the JSON document layout.
+type jsonSnapshot struct {
+	Version    uint            `json:"version"`
+	Entities   []entity.Id     `json:"entities"`
+	Components []jsonComponent `json:"components"`
+	Tags       []jsonTag       `json:"tags"`
+}
+
+// jsonComponent holds a component's entities and their encoded values (same order).
+type jsonComponent struct {
+	Name     string            `json:"name"`
+	Entities []entity.Id       `json:"entities"`
+	Values   []json.RawMessage `json:"values"`
+}
+
+// jsonTag holds a tag's entities.
+type jsonTag struct {
+	Name     string      `json:"name"`
+	Entities []entity.Id `json:"entities"`
+}
+
+// EncodeJSON writes the snapshot as a JSON document.
+//
+// Components are encoded with encoding/json, so types can customize their encoding by implementing json.Marshaler.
+func EncodeJSON(writer io.Writer, data *Snapshot) error {
+	document := jsonSnapshot{
+		Version:    jsonVersion,
+		Entities:   expand(data.Entities),
+		Components: make([]jsonComponent, len(data.Components)),
+		Tags:       make([]jsonTag, len(data.Tags)),
+	}
+
+	for index, column := range data.Components {
+		values := make([]json.RawMessage, len(column.Values))
+		for valueIndex, value := range column.Values {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("snapshot: encoding %q: %w", column.Name, err)
+			}
+			values[valueIndex] = encoded
+		}
+		document.Components[index] = jsonComponent{Name: column.Name, Entities: expand(column.Entities), Values: values}
+	}
+
+	for index, column := range data.Tags {
+		document.Tags[index] = jsonTag{Name: column.Name, Entities: expand(column.Entities)}
+	}
+
+	return json.NewEncoder(writer).Encode(&document)
+}
+
+// DecodeJSON reads a JSON document, decoding component values into the types registered in the link manager.
+func DecodeJSON(reader io.Reader, componentLinkManager api.ComponentLinkManager) (*Snapshot, error) {
+	var document jsonSnapshot
+	if err := json.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, fmt.Errorf("%w: %w", snapshot.ErrInvalidSnapshot, err)
+	}
+	if document.Version != jsonVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", snapshot.ErrInvalidSnapshot, document.Version)
+	}
+
+	entities, err := collapse("entities", document.Entities)
+	if err != nil {
+		return nil, err
+	}
+	data := &Snapshot{
+		Entities:   entities,
+		Components: make([]Column, len(document.Components)),
+		Tags:       make([]Column, len(document.Tags)),
+	}
+
+	for index, encoded := range document.Components {
+		linker, ok := componentLinkManager.LookupComponent(encoded.Name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", snapshot.ErrUnknownComponent, encoded.Name)
+		}
+		instanceLinker, ok := linker.(api.InstanceLinker)
+		if !ok || len(encoded.Values) != len(encoded.Entities) {
+			return nil, fmt.Errorf("%w: component %q", snapshot.ErrInvalidSnapshot, encoded.Name)
+		}
+
+		column := Column{Name: encoded.Name, Values: make([]any, len(encoded.Values))}
+		if column.Entities, err = collapse(encoded.Name, encoded.Entities); err != nil {
+			return nil, err
+		}
+
+		// Values are stored in ascending entity order, regardless of the document order
+		order := make([]int, len(encoded.Entities))
+		for position := range order {
+			order[position] = position
+		}
+		slices.SortFunc(order, func(a, b int) int {
+			return cmp.Compare(encoded.Entities[a], encoded.Entities[b])
+		})
+
+		for valueIndex, position := range order {
+			instance := instanceLinker.NewInstance()
+			if err = json.Unmarshal(encoded.Values[position], instance); err != nil {
+				return nil, fmt.Errorf("%w: decoding %q: %w", snapshot.ErrInvalidSnapshot, encoded.Name, err)
+			}
+			column.Values[valueIndex] = instance
+		}
+		data.Components[index] = column
+	}
+
+	for index, encoded := range document.Tags {
+		data.Tags[index] = Column{Name: encoded.Name}
+		if data.Tags[index].Entities, err = collapse(encoded.Name, encoded.Entities); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = validate(data, componentLinkManager); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// expand converts the mask into a slice of entity ids.
+func expand(mask bit.Mask) []entity.Id {
+	entityIds := make([]entity.Id, 0, mask.Count())
+	forEach(mask, func(entityId entity.Id, _ int) {
+		entityIds = append(entityIds, entityId)
+	})
+	return entityIds
+}
+
+// collapse converts the entity ids into a mask, rejecting duplicates and ids above maxEntityId.
+func collapse(name string, entityIds []entity.Id) (bit.Mask, error) {
+	entities := bitset.New(0)
+	for _, entityId := range entityIds {
+		if entityId > maxEntityId {
+			return nil, fmt.Errorf("%w: %q lists entity %d, above the maximum %d", snapshot.ErrInvalidSnapshot, name, entityId, maxEntityId)
+		}
+		if entities.Test(entityId) {
+			return nil, fmt.Errorf("%w: %q lists entity %d twice", snapshot.ErrInvalidSnapshot, name, entityId)
+		}
+		entities.Set(entityId)
+	}
+	return bit.NewMask(entities), nil
+}
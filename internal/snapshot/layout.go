@@ -0,0 +1,91 @@
+package snapshot
+
+import (
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+)
+
+// LayoutHash returns a hash of the encoded layout of the type (exported field names and kinds, recursively).
+//
+// Only the shape of the data is hashed, so renaming the type or its package doesn't change the hash.
+func LayoutHash(instanceType reflect.Type) uint64 {
+	var stringBuilder strings.Builder
+	describeLayout(&stringBuilder, instanceType, make(map[reflect.Type]bool))
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(stringBuilder.String()))
+	return hash.Sum64()
+}
+
+// layoutOf returns the layout hash of the linker's component type (or of its schema, for dynamic components).
+func layoutOf(instanceLinker api.InstanceLinker) uint64 {
+	if dynamicLinker, ok := instanceLinker.(api.DynamicLinker); ok {
+		return schemaHash(dynamicLinker.Schema())
+	}
+	return LayoutHash(reflect.TypeOf(instanceLinker.NewInstance()).Elem())
+}
+
+// schemaHash returns a hash of the fields of a dynamic component (names and kinds, in declaration order).
+func schemaHash(schema *component.Schema) uint64 {
+	var stringBuilder strings.Builder
+	stringBuilder.WriteString("record{")
+	for _, field := range schema.Fields() {
+		stringBuilder.WriteString(field.Name)
+		stringBuilder.WriteString(" ")
+		stringBuilder.WriteString(field.Kind.String())
+		stringBuilder.WriteString(";")
+	}
+	stringBuilder.WriteString("}")
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(stringBuilder.String()))
+	return hash.Sum64()
+}
+
+// describeLayout appends a description of the type layout to the string builder.
+func describeLayout(stringBuilder *strings.Builder, instanceType reflect.Type, visited map[reflect.Type]bool) {
+	// Recursive types are described by name after their first occurrence
+	if visited[instanceType] {
+		stringBuilder.WriteString(instanceType.String())
+		return
+	}
+
+	switch instanceType.Kind() {
+	case reflect.Struct:
+		visited[instanceType] = true
+		stringBuilder.WriteString("struct{")
+		for index := range instanceType.NumField() {
+			field := instanceType.Field(index)
+			if !field.IsExported() {
+				continue
+			}
+			stringBuilder.WriteString(field.Name)
+			stringBuilder.WriteString(" ")
+			describeLayout(stringBuilder, field.Type, visited)
+			stringBuilder.WriteString(";")
+		}
+		stringBuilder.WriteString("}")
+		delete(visited, instanceType)
+	case reflect.Pointer:
+		stringBuilder.WriteString("*")
+		describeLayout(stringBuilder, instanceType.Elem(), visited)
+	case reflect.Slice:
+		stringBuilder.WriteString("[]")
+		describeLayout(stringBuilder, instanceType.Elem(), visited)
+	case reflect.Array:
+		stringBuilder.WriteString("[" + strconv.Itoa(instanceType.Len()) + "]")
+		describeLayout(stringBuilder, instanceType.Elem(), visited)
+	case reflect.Map:
+		stringBuilder.WriteString("map[")
+		describeLayout(stringBuilder, instanceType.Key(), visited)
+		stringBuilder.WriteString("]")
+		describeLayout(stringBuilder, instanceType.Elem(), visited)
+	default:
+		stringBuilder.WriteString(instanceType.Kind().String())
+	}
+}
@@ -0,0 +1,33 @@
+package snapshot
+
+// Migration converts instances saved with an older layout into the current component type.
+type Migration struct {
+	// NewInstance returns a pointer to a new instance of the older layout.
+	NewInstance func() any
+	// Migrate fills the current instance from the decoded older instance.
+	Migrate func(old, current any)
+}
+
+// Migrations holds the registered migrations, by component label and source layout hash.
+type Migrations struct {
+	migrations map[string]map[uint64]Migration
+}
+
+// NewMigrations creates an empty migration registry.
+func NewMigrations() *Migrations {
+	return &Migrations{migrations: make(map[string]map[uint64]Migration)}
+}
+
+// Register adds a migration for the component label, applied to snapshots storing the given layout hash.
+func (m *Migrations) Register(name string, layoutHash uint64, migration Migration) {
+	if _, ok := m.migrations[name]; !ok {
+		m.migrations[name] = make(map[uint64]Migration)
+	}
+	m.migrations[name][layoutHash] = migration
+}
+
+// find returns the migration for the component label and layout hash.
+func (m *Migrations) find(name string, layoutHash uint64) (Migration, bool) {
+	migration, ok := m.migrations[name][layoutHash]
+	return migration, ok
+}
@@ -0,0 +1,124 @@
+package snapshot
+
+import (
+	"reflect"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+)
+
+// refType is the type of entity references rewritten on import.
+var refType = reflect.TypeFor[component.Ref]()
+
+// remapper rewrites the entity references of component instances.
+type remapper struct {
+	remap           func(entity.Id) (entity.Id, bool)
+	references      map[reflect.Type]bool
+	visitedPointers map[uintptr]bool
+}
+
+func newRemapper(newIds map[entity.Id]entity.Id) *remapper {
+	return &remapper{
+		remap: func(entityId entity.Id) (entity.Id, bool) {
+			newId, ok := newIds[entityId]
+			return newId, ok
+		},
+		references: make(map[reflect.Type]bool),
+	}
+}
+
+// rewrite rewrites the references of the instance (a pointer to a component).
+//
+// Components implementing component.Remapper rewrite their own references, otherwise exported Ref fields
+// are rewritten (references to entities outside the import are cleared).
+func (r *remapper) rewrite(instance any) {
+	if custom, ok := instance.(component.Remapper); ok {
+		custom.RemapEntities(r.remap)
+		return
+	}
+
+	value := reflect.ValueOf(instance).Elem()
+	if !r.hasReferences(value.Type()) {
+		return
+	}
+	r.visitedPointers = make(map[uintptr]bool)
+	r.rewriteValue(value)
+}
+
+// rewriteValue rewrites the Ref values reachable from the (settable) value.
+func (r *remapper) rewriteValue(value reflect.Value) {
+	if !r.hasReferences(value.Type()) {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() == refType {
+			reference := value.Addr().Interface().(*component.Ref)
+			if reference.Valid {
+				reference.Id, reference.Valid = r.remap(reference.Id)
+			}
+			return
+		}
+		for index := range value.NumField() {
+			if field := value.Field(index); field.CanSet() {
+				r.rewriteValue(field)
+			}
+		}
+	case reflect.Pointer:
+		// Shared (or cyclic) pointers are only rewritten once
+		if value.IsNil() || r.visitedPointers[value.Pointer()] {
+			return
+		}
+		r.visitedPointers[value.Pointer()] = true
+		r.rewriteValue(value.Elem())
+	case reflect.Slice, reflect.Array:
+		for index := range value.Len() {
+			r.rewriteValue(value.Index(index))
+		}
+	case reflect.Map:
+		iterator := value.MapRange()
+		for iterator.Next() {
+			element := reflect.New(value.Type().Elem()).Elem()
+			element.Set(iterator.Value())
+			r.rewriteValue(element)
+			value.SetMapIndex(iterator.Key(), element)
+		}
+	default:
+	}
+}
+
+// hasReferences returns true if Ref values are reachable from the type (through exported fields).
+func (r *remapper) hasReferences(valueType reflect.Type) bool {
+	result, ok := r.references[valueType]
+	if !ok {
+		result = reachesReferences(valueType, make(map[reflect.Type]bool))
+		r.references[valueType] = result
+	}
+	return result
+}
+
+// reachesReferences searches the type graph for Ref values, skipping the types being visited.
+func reachesReferences(valueType reflect.Type, visiting map[reflect.Type]bool) bool {
+	if valueType == refType {
+		return true
+	}
+	if visiting[valueType] {
+		return false
+	}
+	visiting[valueType] = true
+
+	switch valueType.Kind() {
+	case reflect.Struct:
+		for index := range valueType.NumField() {
+			field := valueType.Field(index)
+			if field.IsExported() && reachesReferences(field.Type, visiting) {
+				return true
+			}
+		}
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return reachesReferences(valueType.Elem(), visiting)
+	default:
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package snapshot
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/api"
+	internalComponent "github.com/andrei-cosmin/sandecs/internal/component"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+)
+
+// Snapshot is an in-memory capture of the sandbox contents, keyed by component labels.
+type Snapshot struct {
+	Entities   bit.Mask
+	Components []Column
+	Tags       []Column
+}
+
+// Column holds the entities linked to a component or tag, along with their instances and layout hash (components only).
+// Values are stored in ascending entity order.
+type Column struct {
+	Name     string
+	Layout   uint64
+	Entities bit.Mask
+	Values   []any
+}
+
+// Capture creates a snapshot of the linked entities, components and tags.
+//
+// Bitmasks and component values are referenced, not copied, so the snapshot must be encoded before the sandbox changes.
+func Capture(entityLinker entity.MaskView, componentLinkManager api.ComponentLinkManager) *Snapshot {
+	result := &Snapshot{
+		Entities:   entityLinker.EntityMask(),
+		Components: make([]Column, 0),
+		Tags:       make([]Column, 0),
+	}
+
+	for componentId := range component.Id(componentLinkManager.Size()) {
+		linker := componentLinkManager.Get(componentId)
+		column := Column{
+			Name:     linker.ComponentType(),
+			Entities: linker.EntityMask(),
+		}
+
+		instanceLinker, ok := linker.(api.InstanceLinker)
+		if !ok {
+			result.Tags = append(result.Tags, column)
+			continue
+		}
+
+		column.Layout = layoutOf(instanceLinker)
+		column.Values = make([]any, 0, column.Entities.Count())
+		forEach(column.Entities, func(entityId entity.Id, _ int) {
+			column.Values = append(column.Values, instanceLinker.GetInstance(entityId))
+		})
+		result.Components = append(result.Components, column)
+	}
+
+	// Sort by label, so the output doesn't depend on registration order
+	slices.SortFunc(result.Components, compareColumns)
+	slices.SortFunc(result.Tags, compareColumns)
+	return result
+}
+
+// Restore links the snapshot contents into the sandbox, keeping the original entity IDs.
+//
+// The sandbox must not have any of the snapshot entities linked. Tags missing from the sandbox are registered.
+func Restore(data *Snapshot, entityLinker api.EntityLinker, componentLinkManager api.ComponentLinkManager) {
+	forEach(data.Entities, func(entityId entity.Id, _ int) {
+		entityLinker.LinkId(entityId)
+	})
+
+	for _, column := range data.Components {
+		linker, _ := componentLinkManager.LookupComponent(column.Name)
+		instanceLinker := linker.(api.InstanceLinker)
+		forEach(column.Entities, func(entityId entity.Id, index int) {
+			instanceLinker.SetInstance(entityId, column.Values[index])
+		})
+	}
+
+	for _, column := range data.Tags {
+		tagLinker := internalComponent.RegisterTagLinker(column.Name, componentLinkManager).(api.BatchLinker)
+		tagLinker.LinkBatch(entityIds(column.Entities, nil))
+	}
+}
+
+// Import links the snapshot contents into the sandbox with newly allocated entity IDs, returning the old → new ID map.
+//
+// Entity references in component values are rewritten (see component.Ref and component.Remapper).
+// Tags missing from the sandbox are registered.
+func Import(data *Snapshot, entityLinker api.EntityLinker, componentLinkManager api.ComponentLinkManager) map[entity.Id]entity.Id {
+	newIds := make(map[entity.Id]entity.Id, data.Entities.Count())
+	forEach(data.Entities, func(entityId entity.Id, _ int) {
+		newIds[entityId] = entityLinker.Link()
+	})
+
+	references := newRemapper(newIds)
+	for _, column := range data.Components {
+		linker, _ := componentLinkManager.LookupComponent(column.Name)
+		instanceLinker := linker.(api.InstanceLinker)
+		forEach(column.Entities, func(entityId entity.Id, index int) {
+			references.rewrite(column.Values[index])
+			instanceLinker.SetInstance(newIds[entityId], column.Values[index])
+		})
+	}
+
+	for _, column := range data.Tags {
+		tagLinker := internalComponent.RegisterTagLinker(column.Name, componentLinkManager).(api.BatchLinker)
+		tagLinker.LinkBatch(entityIds(column.Entities, newIds))
+	}
+
+	return newIds
+}
+
+// validate checks that every column refers to snapshot entities, and that component columns refer to registered components.
+func validate(data *Snapshot, componentLinkManager api.ComponentLinkManager) error {
+	for _, column := range data.Components {
+		if err := validateComponent(column, componentLinkManager); err != nil {
+			return err
+		}
+		if err := validateEntities(data, column); err != nil {
+			return err
+		}
+	}
+
+	for _, column := range data.Tags {
+		if err := validateEntities(data, column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateComponent checks that the column refers to a registered component, with one value per entity
+// (shared by snapshots and deltas).
+func validateComponent(column Column, componentLinkManager api.ComponentLinkManager) error {
+	linker, ok := componentLinkManager.LookupComponent(column.Name)
+	if !ok {
+		return fmt.Errorf("%w: %q", snapshot.ErrUnknownComponent, column.Name)
+	}
+	if _, ok = linker.(api.InstanceLinker); !ok || uint(len(column.Values)) != column.Entities.Count() {
+		return fmt.Errorf("%w: component %q", snapshot.ErrInvalidSnapshot, column.Name)
+	}
+	return nil
+}
+
+func validateEntities(data *Snapshot, column Column) error {
+	if data.Entities.DifferenceCardinality(column.Entities.Clone()) != 0 {
+		return fmt.Errorf("%w: %q references unknown entities", snapshot.ErrInvalidSnapshot, column.Name)
+	}
+	return nil
+}
+
+func compareColumns(a, b Column) int {
+	return cmp.Compare(a.Name, b.Name)
+}
+
+// entityIds expands the mask into entity IDs, optionally mapped through the given ID map.
+func entityIds(mask bit.Mask, idMap map[entity.Id]entity.Id) []entity.Id {
+	ids := make([]entity.Id, 0, mask.Count())
+	forEach(mask, func(entityId entity.Id, _ int) {
+		if idMap != nil {
+			entityId = idMap[entityId]
+		}
+		ids = append(ids, entityId)
+	})
+	return ids
+}
+
+// forEach calls the function for every set bit, along with its position among the set bits.
+func forEach(mask bit.Mask, function func(entityId entity.Id, index int)) {
+	index := 0
+	for entityId, hasNext := mask.NextSet(0); hasNext; entityId, hasNext = mask.NextSet(entityId + 1) {
+		function(entityId, index)
+		index++
+	}
+}
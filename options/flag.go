@@ -0,0 +1,19 @@
+package options
+
+// Flag enables optional sandbox behavior (flags can be combined).
+type Flag byte
+
+// Sandbox flags.
+const (
+	// Strict reports API misuse by panicking with typed errors (see component.LinkError and filter.ErrFilterAfterStart),
+	// instead of silently returning nil or false. Meant for tests and dev builds.
+	Strict Flag = 1 << iota
+
+	// Validate checks the sandbox invariants after each Update, panicking if they don't hold (see sandbox.Validate).
+	// Meant for tests, as it scans every linker and filter.
+	Validate
+
+	// TrackViews records where filter views are created and detects views garbage collected without being released
+	// (see sandbox.Views). Meant for debug builds.
+	TrackViews
+)
@@ -10,6 +10,20 @@ const (
 	Compact              // Dense storage via sparse set
 )
 
+// String returns the mode name.
+func (m Mode) String() string {
+	switch m {
+	case Standard:
+		return "standard"
+	case Pooled:
+		return "pooled"
+	case Compact:
+		return "compact"
+	default:
+		return "unknown"
+	}
+}
+
 // Default sandbox configuration values.
 const (
 	DefaultNumEntities   = 128
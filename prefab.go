@@ -0,0 +1,97 @@
+package sandbox
+
+import (
+	"reflect"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/sandbox"
+)
+
+// Prefab is a template of components (with default values) and tags, used to spawn entities.
+type Prefab struct {
+	parent     *Prefab
+	components []prefabComponent
+	tags       []component.Tag
+}
+
+// prefabComponent holds a component default value, keyed by type for overrides.
+type prefabComponent struct {
+	componentType reflect.Type
+	spawn         sandbox.SpawnComponent
+}
+
+// NewPrefab creates an empty prefab.
+func NewPrefab() *Prefab {
+	return &Prefab{}
+}
+
+// Extend creates a prefab inheriting the components and tags of p.
+// Components added to the new prefab override the inherited ones of the same type.
+func (p *Prefab) Extend() *Prefab {
+	return &Prefab{parent: p}
+}
+
+// Tag adds tags to the prefab.
+func (p *Prefab) Tag(tags ...component.Tag) *Prefab {
+	p.tags = append(p.tags, tags...)
+	return p
+}
+
+// WithComponent adds component T with a default value to the prefab (replacing a previous value of the same type).
+// Values are copied shallowly into spawned entities, unless they implement component.Cloner.
+func WithComponent[T component.Component](p *Prefab, value T) *Prefab {
+	added := prefabComponent{
+		componentType: reflect.TypeFor[T](),
+		spawn:         sandbox.NewSpawnComponent(value),
+	}
+	for index := range p.components {
+		if p.components[index].componentType == added.componentType {
+			p.components[index] = added
+			return p
+		}
+	}
+	p.components = append(p.components, added)
+	return p
+}
+
+// Spawn creates an entity with the prefab components and tags.
+func (p *Prefab) Spawn(s *Sandbox) entity.Id {
+	return p.SpawnN(s, 1)[0]
+}
+
+// SpawnN creates count entities with the prefab components and tags, linking each component in one batch.
+func (p *Prefab) SpawnN(s *Sandbox, count uint) []entity.Id {
+	components, tags := p.resolve()
+	return s.internal.Spawn(count, components, tags)
+}
+
+// resolve flattens the prefab hierarchy, applying overrides from the root prefab down to p.
+func (p *Prefab) resolve() ([]sandbox.SpawnComponent, []component.Tag) {
+	hierarchy := make([]*Prefab, 0)
+	for prefab := p; prefab != nil; prefab = prefab.parent {
+		hierarchy = append(hierarchy, prefab)
+	}
+
+	indices := make(map[reflect.Type]int)
+	components := make([]sandbox.SpawnComponent, 0)
+	linkedTags := make(map[component.Tag]bool)
+	tags := make([]component.Tag, 0)
+	for index := len(hierarchy) - 1; index >= 0; index-- {
+		for _, prefabComponent := range hierarchy[index].components {
+			if position, ok := indices[prefabComponent.componentType]; ok {
+				components[position] = prefabComponent.spawn
+				continue
+			}
+			indices[prefabComponent.componentType] = len(components)
+			components = append(components, prefabComponent.spawn)
+		}
+		for _, tag := range hierarchy[index].tags {
+			if !linkedTags[tag] {
+				linkedTags[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return components, tags
+}
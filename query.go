@@ -0,0 +1,240 @@
+package sandbox
+
+import (
+	"slices"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/internal/sandbox"
+)
+
+// Query1 iterates over the entities matching its filters, along with their component A.
+type Query1[A component.Component] struct {
+	view filter.View
+	a    *column[A]
+}
+
+// Query2 iterates over the entities matching its filters, along with their components A and B.
+type Query2[A, B component.Component] struct {
+	view filter.View
+	a    *column[A]
+	b    *column[B]
+}
+
+// Query3 iterates over the entities matching its filters, along with their components A, B and C.
+type Query3[A, B, C component.Component] struct {
+	view filter.View
+	a    *column[A]
+	b    *column[B]
+	c    *column[C]
+}
+
+// declaration holds the query declarations of a component (see filter.Optional, filter.Read and filter.Write).
+type declaration struct {
+	optional bool
+	read     bool
+	write    bool
+}
+
+// column provides the component T of the entities iterated by a typed query, according to its declarations.
+type column[T component.Component] struct {
+	linker component.Linker[T]
+	declaration
+	value T // Copy of the current component, for read-only access
+}
+
+// NewQuery1 creates a query over the entities with component A, matching the given filters.
+// A is required, unless marked with filter.Optional (the query then yields nil for entities without it).
+func NewQuery1[A component.Component](s *Sandbox, options ...filter.QueryOption) *Query1[A] {
+	filters, declarations := queryOptions(s, options)
+	a := newColumn[A](s, declarations)
+	return &Query1[A]{
+		view: Filter(s, queryFilters(filters, a.match())...),
+		a:    a,
+	}
+}
+
+// NewQuery2 creates a query over the entities with components A and B, matching the given filters.
+// Components are required, unless marked with filter.Optional (the query then yields nil for entities without them):
+//
+//	sprites := sandbox.NewQuery2[Sprite, Tint](sb, filter.Optional[Tint](), filter.Read[Tint](), filter.Write[Sprite]())
+//
+// At least one component must be required, or the filters must match entities on their own (panics with filter.ErrEmptyQuery otherwise).
+// Access to components is declared with filter.Read and filter.Write (see Access). Declarations are query options
+// alongside the filters, but aren't filters themselves: Filter doesn't accept them.
+func NewQuery2[A, B component.Component](s *Sandbox, options ...filter.QueryOption) *Query2[A, B] {
+	filters, declarations := queryOptions(s, options)
+	a, b := newColumn[A](s, declarations), newColumn[B](s, declarations)
+	return &Query2[A, B]{
+		view: Filter(s, queryFilters(filters, a.match(), b.match())...),
+		a:    a,
+		b:    b,
+	}
+}
+
+// NewQuery3 creates a query over the entities with components A, B and C, matching the given filters.
+// Components are required, unless marked with filter.Optional (the query then yields nil for entities without them).
+// Access to components is declared with filter.Read and filter.Write (see Access).
+func NewQuery3[A, B, C component.Component](s *Sandbox, options ...filter.QueryOption) *Query3[A, B, C] {
+	filters, declarations := queryOptions(s, options)
+	a, b, c := newColumn[A](s, declarations), newColumn[B](s, declarations), newColumn[C](s, declarations)
+	return &Query3[A, B, C]{
+		view: Filter(s, queryFilters(filters, a.match(), b.match(), c.match())...),
+		a:    a,
+		b:    b,
+		c:    c,
+	}
+}
+
+// Each calls the function for each matched entity, with its component (nil if optional and absent).
+// Components declared with filter.Read are copies, valid during the call; components declared with filter.Write are marked changed after it.
+func (q *Query1[A]) Each(function func(entityId entity.Id, a *A)) {
+	for _, entityId := range q.view.EntityIds() {
+		a := q.a.get(entityId)
+		function(entityId, a)
+		q.a.done(entityId, a)
+	}
+}
+
+// Access returns the declared access of the query to its components (for scheduling).
+func (q *Query1[A]) Access() []filter.Access {
+	return []filter.Access{q.a.access()}
+}
+
+// View returns the view of the matched entities.
+func (q *Query1[A]) View() filter.View {
+	return q.view
+}
+
+// Release releases the query view (see filter.View).
+func (q *Query1[A]) Release() {
+	q.view.Release()
+}
+
+// Each calls the function for each matched entity, with its components (nil if optional and absent).
+// Components declared with filter.Read are copies, valid during the call; components declared with filter.Write are marked changed after it.
+func (q *Query2[A, B]) Each(function func(entityId entity.Id, a *A, b *B)) {
+	for _, entityId := range q.view.EntityIds() {
+		a, b := q.a.get(entityId), q.b.get(entityId)
+		function(entityId, a, b)
+		q.a.done(entityId, a)
+		q.b.done(entityId, b)
+	}
+}
+
+// Access returns the declared access of the query to its components (for scheduling).
+func (q *Query2[A, B]) Access() []filter.Access {
+	return []filter.Access{q.a.access(), q.b.access()}
+}
+
+// View returns the view of the matched entities.
+func (q *Query2[A, B]) View() filter.View {
+	return q.view
+}
+
+// Release releases the query view (see filter.View).
+func (q *Query2[A, B]) Release() {
+	q.view.Release()
+}
+
+// Each calls the function for each matched entity, with its components (nil if optional and absent).
+// Components declared with filter.Read are copies, valid during the call; components declared with filter.Write are marked changed after it.
+func (q *Query3[A, B, C]) Each(function func(entityId entity.Id, a *A, b *B, c *C)) {
+	for _, entityId := range q.view.EntityIds() {
+		a, b, c := q.a.get(entityId), q.b.get(entityId), q.c.get(entityId)
+		function(entityId, a, b, c)
+		q.a.done(entityId, a)
+		q.b.done(entityId, b)
+		q.c.done(entityId, c)
+	}
+}
+
+// Access returns the declared access of the query to its components (for scheduling).
+func (q *Query3[A, B, C]) Access() []filter.Access {
+	return []filter.Access{q.a.access(), q.b.access(), q.c.access()}
+}
+
+// View returns the view of the matched entities.
+func (q *Query3[A, B, C]) View() filter.View {
+	return q.view
+}
+
+// Release releases the query view (see filter.View).
+func (q *Query3[A, B, C]) Release() {
+	q.view.Release()
+}
+
+// queryOptions splits the options of a typed query into its filters and its declarations (by component ID).
+func queryOptions(s *Sandbox, options []filter.QueryOption) ([]filter.Filter, map[component.Id]declaration) {
+	filters := make([]filter.Filter, 0, len(options))
+	declarations := make(map[component.Id]declaration)
+	for _, option := range options {
+		switch current := option.(type) {
+		case filter.Filter:
+			filters = append(filters, current)
+		case filter.Declaration:
+			for _, queryDeclaration := range current.Declarations {
+				s.internal.Accept(queryDeclaration.Registration())
+				declarationType := queryDeclaration.DeclarationType()
+				declared := declarations[queryDeclaration.ComponentId()]
+				declared.optional = declared.optional || declarationType == sandbox.Optional
+				declared.read = declared.read || declarationType == sandbox.Read
+				declared.write = declared.write || declarationType == sandbox.Write
+				declarations[queryDeclaration.ComponentId()] = declared
+			}
+		}
+	}
+	return filters, declarations
+}
+
+// queryFilters returns the filters of a typed query: the given filters, along with the match rules of the required components.
+// Panics with filter.ErrEmptyQuery if there are no rules (the query would never match any entity).
+func queryFilters(filters []filter.Filter, matches ...[]filter.Filter) []filter.Filter {
+	concatenated := slices.Concat(append([][]filter.Filter{filters}, matches...)...)
+	for _, current := range concatenated {
+		if len(current.Rules) > 0 {
+			return concatenated
+		}
+	}
+	panic(filter.ErrEmptyQuery)
+}
+
+// newColumn creates the column of component T, with its declarations.
+func newColumn[T component.Component](s *Sandbox, declarations map[component.Id]declaration) *column[T] {
+	linker := ComponentLinker[T](s)
+	return &column[T]{linker: linker, declaration: declarations[linker.ComponentId()]}
+}
+
+// match returns the filter matching the component, or none if it is optional.
+func (c *column[T]) match() []filter.Filter {
+	if c.optional {
+		return nil
+	}
+	return []filter.Filter{filter.Match[T]()}
+}
+
+// access returns the access to the component (reported as written unless only declared read).
+func (c *column[T]) access() filter.Access {
+	return filter.Access{ComponentId: c.linker.ComponentId(), Write: c.write || !c.read}
+}
+
+// get returns the entity's component (a copy for read-only access), or nil if not linked.
+func (c *column[T]) get(entityId entity.Id) *T {
+	if !c.linker.Has(entityId) {
+		return nil
+	}
+	instance := c.linker.Get(entityId)
+	if c.read && !c.write {
+		c.value = *instance
+		return &c.value
+	}
+	return instance
+}
+
+// done marks the entity's component changed, for mutable access.
+func (c *column[T]) done(entityId entity.Id, instance *T) {
+	if c.write && instance != nil {
+		c.linker.MarkChanged(entityId)
+	}
+}
@@ -13,10 +13,14 @@ type Sandbox struct {
 	internal *sandbox.Sandbox
 }
 
-// New creates a sandbox with pre-allocated capacity.
-func New(mode options.Mode, numEntities, numComponents, poolCapacity uint) *Sandbox {
+// New creates a sandbox with pre-allocated capacity, and optional flags (see options.Strict).
+func New(mode options.Mode, numEntities, numComponents, poolCapacity uint, flags ...options.Flag) *Sandbox {
+	var combined options.Flag
+	for _, flag := range flags {
+		combined |= flag
+	}
 	return &Sandbox{
-		internal: sandbox.New(mode, numEntities, numComponents, poolCapacity),
+		internal: sandbox.New(mode, numEntities, numComponents, poolCapacity, combined),
 	}
 }
 
@@ -26,8 +30,12 @@ func NewDefault() *Sandbox {
 }
 
 // Filter creates a view of entities matching the given filters.
-// Register filters during initialization.
-func Filter(s *Sandbox, filters ...filter.Filter) entity.View {
+// Register filters during initialization (in strict mode, panics with filter.ErrFilterAfterStart after the first Update).
+// Views with identical rules share their cache; release views that are no longer needed, so the cache stops being updated.
+func Filter(s *Sandbox, filters ...filter.Filter) filter.View {
+	if s.internal.IsStrict() && s.internal.IsStarted() {
+		panic(filter.ErrFilterAfterStart)
+	}
 	rules := make([]sandbox.Rule, 0)
 	for index := range filters {
 		rules = append(rules, filters[index].Rules...)
@@ -35,6 +43,51 @@ func Filter(s *Sandbox, filters ...filter.Filter) entity.View {
 	return sandbox.LinkFilter(s.internal, rules)
 }
 
+// QueryOnce returns the IDs of the entities currently matching the given filters, in ascending order.
+// The filters are evaluated directly (including links not yet processed by Update), without registering a cached filter:
+// use it for one-off queries (e.g. console commands), and Filter for queries repeated every frame.
+func QueryOnce(s *Sandbox, filters ...filter.Filter) []entity.Id {
+	rules := make([]sandbox.Rule, 0)
+	for index := range filters {
+		rules = append(rules, filters[index].Rules...)
+	}
+	return sandbox.QueryOnce(s.internal, rules)
+}
+
+// SortedView returns a view of the given view's entities, ordered by their component T using the less function
+// (entities with equal components are ordered by ID). The entities of the view must have the component T.
+// The order is maintained incrementally on each Update, as entities enter or exit the view and as their component T is linked or marked changed
+// (components modified without MarkChanged keep their position). Only those entities are re-sorted.
+// Release the view once unused.
+func SortedView[T component.Component](s *Sandbox, view entity.View, less func(a, b *T) bool) filter.SortedView {
+	return sandbox.SortedView(s.internal, view, ComponentLinker[T](s), less)
+}
+
+// GroupBy partitions the given view's entities by the key computed from their component T.
+// The entities of the view must have the component T. The groups are kept up to date on each Update,
+// as entities enter or exit the view and as their component T is linked or marked changed
+// (only those entities are regrouped). Release the groups once unused.
+func GroupBy[T component.Component, K comparable](s *Sandbox, view entity.View, key func(*T) K) filter.Groups[K] {
+	return sandbox.GroupBy(s.internal, view, ComponentLinker[T](s), key)
+}
+
+// Index maps the unique keys computed from component T (e.g. network IDs) to the entities linked to it.
+// The index is updated at the end of each Update, as the component is linked, unlinked or marked changed.
+// If several entities claim the same key, the first one keeps it (the others take over once it is released),
+// and the conflict is reported through Conflicts and the OnConflict callback.
+// The entities already linked are indexed on first use (so that OnConflict can be set first). Release the index once unused.
+func Index[T component.Component, K comparable](s *Sandbox, key func(*T) K) filter.Index[K] {
+	return sandbox.Index(s.internal, ComponentLinker[T](s), key)
+}
+
+// Observe registers callbacks invoked at the end of each Update: onChange for the entities that linked component T
+// or marked it changed since the previous Update, and onUnlink for the entities that unlinked it (or were removed).
+// The entities already linked to component T are reported to onChange right away.
+// Use it to maintain external structures (e.g. spatial indexes) incrementally, and release the observer once unused.
+func Observe[T component.Component](s *Sandbox, onChange func(entity.Id, *T), onUnlink func(entity.Id)) filter.Observer {
+	return sandbox.Observe(s.internal, ComponentLinker[T](s), onChange, onUnlink)
+}
+
 // LinkEntity creates a new entity and returns its ID.
 func LinkEntity(s *Sandbox) entity.Id {
 	return s.internal.LinkEntity()
@@ -45,11 +98,25 @@ func UnlinkEntity(s *Sandbox, entityId entity.Id) {
 	s.internal.UnlinkEntity(entityId)
 }
 
+// TryUnlinkEntity removes the entity and all its components, or returns an error describing why it can't:
+// component.ErrEntityNotLinked if it doesn't exist, component.ErrStaleEntity if it is already scheduled for removal.
+func TryUnlinkEntity(s *Sandbox, entityId entity.Id) error {
+	return s.internal.TryUnlinkEntity(entityId)
+}
+
 // IsEntityLinked returns true if the entity exists.
 func IsEntityLinked(s *Sandbox, entityId entity.Id) bool {
 	return s.internal.IsEntityLinked(entityId)
 }
 
+// CloneEntity creates a new entity with copies of the source entity's components and tags, and returns its ID.
+// Components are copied shallowly, unless they implement component.Cloner. Link hooks fire as for regular links.
+// Returns false, without linking an entity, if the source entity doesn't exist. The result is reported separately,
+// rather than as a sentinel ID, because every entity ID (including 0) may refer to a live entity.
+func CloneEntity(s *Sandbox, sourceId entity.Id) (entity.Id, bool) {
+	return s.internal.CloneEntity(sourceId)
+}
+
 // ComponentLinker returns the linker for component type T.
 func ComponentLinker[T component.Component](s *Sandbox) component.Linker[T] {
 	registration := sandbox.ComponentRegistration[T]{}
@@ -57,6 +124,23 @@ func ComponentLinker[T component.Component](s *Sandbox) component.Linker[T] {
 	return registration.GetLinker()
 }
 
+// RegisterName registers component T under the given name, used instead of its type name by snapshots,
+// name-based access and introspection. Call it before T is used, so saved data doesn't depend on Go type names:
+//
+//	err := sandbox.RegisterName[physics.Position](sb, "physics/position")
+//
+// Components are identified by their Go type, so types with the same name from different packages are distinct
+// components. Without an explicit name, a component is named after its type with its full package path
+// (e.g. "example.com/game/physics.Position"), whatever the registration order. Tags use a separate namespace,
+// so a tag may share a component's name.
+//
+// Returns component.ErrNameConflict if the name is used by another component, or if T is already registered under another name.
+func RegisterName[T component.Component](s *Sandbox, name string) error {
+	registration := sandbox.NewNameRegistration[T](name)
+	s.internal.Accept(registration)
+	return registration.Err()
+}
+
 // TagLinker returns the linker for the given tag.
 func TagLinker(s *Sandbox, tag component.Tag) component.TagLinker {
 	registration := sandbox.NewTagRegistration(tag)
@@ -64,10 +148,39 @@ func TagLinker(s *Sandbox, tag component.Tag) component.TagLinker {
 	return registration.GetLinker()
 }
 
+// TypedTagLinker returns the linker for the typed tag T, a zero-sized struct type (e.g. `type Rendered struct{}`).
+// Typed tags store no values, like string tags, but typos are caught at compile time. They are matched with filter.MatchTag,
+// and are named after their type with its full package path (e.g. "example.com/game.Rendered"), a name shared with string tags for data-driven use.
+// Panics with component.ErrTagType if T isn't a zero-sized struct.
+func TypedTagLinker[T component.Component](s *Sandbox) component.TagLinker {
+	registration := sandbox.TypedTagRegistration[T]{}
+	s.internal.Accept(&registration)
+	return registration.GetLinker()
+}
+
+// DynamicLinker returns the linker for the dynamic component with the given name and fields (a component defined at runtime).
+// Its records are matched by filters through their component ID (see filter.MatchIds), and are included in snapshots,
+// clones and introspection like other components.
+// Returns component.ErrInvalidSchema for invalid fields, or component.ErrSchemaConflict if the name is already registered with other fields.
+func DynamicLinker(s *Sandbox, name string, fields ...component.Field) (component.DynamicLinker, error) {
+	schema, err := component.NewSchema(name, fields...)
+	if err != nil {
+		return nil, err
+	}
+	registration := sandbox.NewDynamicRegistration(schema)
+	s.internal.Accept(registration)
+	return registration.GetLinker()
+}
+
 // Update processes all pending changes. Call once per frame.
 func Update(s *Sandbox) {
-	if s.internal.IsUpdated() {
-		return
+	if !s.internal.IsUpdated() {
+		s.internal.Update()
 	}
-	s.internal.Update()
+	s.internal.EndFrame()
+}
+
+// OnUpdate registers a hook called at the end of each Update (frame boundary), even if there were no pending changes.
+func OnUpdate(s *Sandbox, hook func()) {
+	s.internal.OnUpdate(hook)
 }
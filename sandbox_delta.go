@@ -0,0 +1,32 @@
+package sandbox
+
+import (
+	"io"
+
+	"github.com/andrei-cosmin/sandecs/snapshot"
+)
+
+// Checkpoint records the current state as a baseline for SaveDelta and returns its ID.
+// Call it after Update, once the state was sent (or acknowledged).
+func Checkpoint(s *Sandbox) snapshot.Id {
+	return s.internal.Checkpoint()
+}
+
+// ReleaseCheckpoint drops a baseline that is no longer needed.
+func ReleaseCheckpoint(s *Sandbox, id snapshot.Id) {
+	s.internal.ReleaseCheckpoint(id)
+}
+
+// SaveDelta writes the changes since the baseline checkpoint: created/removed entities,
+// linked/unlinked components and tags, and component values linked or marked changed.
+// A snapshot.None baseline writes the full state.
+func SaveDelta(s *Sandbox, writer io.Writer, baseline snapshot.Id) error {
+	return s.internal.SaveDelta(writer, baseline)
+}
+
+// ApplyDelta applies a delta written by SaveDelta to a mirror sandbox, keeping entity IDs.
+// The mirror must have applied the baseline state and registered the same component linkers.
+// Applying a delta completes a frame: update hooks (see OnUpdate) run after the changes are linked.
+func ApplyDelta(s *Sandbox, reader io.Reader) error {
+	return s.internal.ApplyDelta(reader)
+}
@@ -0,0 +1,66 @@
+package sandbox
+
+import (
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/inspect"
+)
+
+// Components describes all registered components and tags (ID, name, kind, storage mode and linked entity count).
+// Counts reflect the state after the last Update.
+func Components(s *Sandbox) []inspect.Component {
+	return s.internal.Components()
+}
+
+// Filters describes all registered filters (rules by component name and matched entity count).
+// Counts reflect the state after the last Update.
+func Filters(s *Sandbox) []inspect.Filter {
+	return s.internal.Filters()
+}
+
+// EntityComponents describes the components and tags linked to an entity.
+func EntityComponents(s *Sandbox, entityId entity.Id) []inspect.Component {
+	return s.internal.EntityComponents(entityId)
+}
+
+// Views describes the unreleased filter views, which are only recorded with options.TrackViews: views record their creation site,
+// and views garbage collected without being released are reported as leaked.
+func Views(s *Sandbox) []inspect.View {
+	return s.internal.Views()
+}
+
+// Entities returns the IDs of all linked entities, in ascending order.
+func Entities(s *Sandbox) []entity.Id {
+	return s.internal.Entities()
+}
+
+// ComponentValue returns a pointer to the component of an entity (as *T), or nil if not linked or the ID refers to a tag.
+func ComponentValue(s *Sandbox, entityId entity.Id, componentId component.Id) any {
+	return s.internal.ComponentValue(entityId, componentId)
+}
+
+// Validate checks the sandbox invariants, returning an error wrapping inspect.ErrInconsistent if they don't hold:
+// linked components belong to existing entities, scheduled removals are linked, stored instances match the linked entities
+// and every filter matches a fresh recomputation (only checked without pending changes, as filters are refreshed by Update).
+// Use options.Validate to run it automatically after each Update.
+func Validate(s *Sandbox) error {
+	return s.internal.Validate()
+}
+
+// GetByName returns a pointer to the entity's component registered under the given type name (e.g. "game.Position",
+// see Components), or false if the entity doesn't have it. Dynamic components are returned as *component.Record.
+//
+// The pointer references the stored component, so it is only valid until the next Update (like Linker.Get).
+func GetByName(s *Sandbox, entityId entity.Id, name string) (any, bool) {
+	return s.internal.GetByName(entityId, name)
+}
+
+// SetField sets a field of the entity's component registered under the given type name, and marks the component changed.
+// The path selects an exported field, with nested struct fields separated by dots (e.g. "Stats.Health").
+// Numbers are converted to the field type if they fit, and nil sets pointers, slices, maps and interfaces to nil.
+//
+// Returns component.ErrUnknownComponent for unknown names or tags, a *component.LinkError if the entity doesn't have the component,
+// component.ErrUnknownField for unknown or unexported fields, and component.ErrFieldKind if the value doesn't fit the field.
+func SetField(s *Sandbox, entityId entity.Id, name, path string, value any) error {
+	return s.internal.SetField(entityId, name, path, value)
+}
@@ -0,0 +1,38 @@
+package sandbox
+
+import (
+	"io"
+
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/internal/sandbox"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+)
+
+// Save writes all entities, component values and tags to the writer.
+// Components are keyed by type name, so snapshots survive registration-order changes.
+func Save(s *Sandbox, writer io.Writer, format snapshot.Format) error {
+	return s.internal.Save(writer, format)
+}
+
+// Load replaces the sandbox contents with a snapshot written by Save, keeping entity IDs.
+// Component linkers must be registered before loading; missing tags are registered automatically.
+// Loading completes a frame: update hooks (see OnUpdate) run after the snapshot is restored.
+func Load(s *Sandbox, reader io.Reader) error {
+	return s.internal.Load(reader)
+}
+
+// Import links a snapshot written by Save into the sandbox with newly allocated entity IDs,
+// keeping the current contents, and returns the old → new ID map (e.g. to unlink a streamed chunk later).
+// Exported component.Ref fields are rewritten, references to entities outside the snapshot are cleared;
+// components implementing component.Remapper rewrite their own references.
+// Like regular links, the imported components are processed on the next Update.
+func Import(s *Sandbox, reader io.Reader) (map[entity.Id]entity.Id, error) {
+	return s.internal.Import(reader)
+}
+
+// RegisterMigration registers a conversion used when loading binary snapshots
+// that stored component T with the field layout of Old (an older version of T).
+func RegisterMigration[Old any, T component.Component](s *Sandbox, migrate func(old *Old, current *T)) {
+	sandbox.RegisterMigration(s.internal, migrate)
+}
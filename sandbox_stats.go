@@ -0,0 +1,15 @@
+package sandbox
+
+import "github.com/andrei-cosmin/sandecs/inspect"
+
+// Stats returns the statistics of the last frame: linked and unlinked entities (in total and per component),
+// filter recomputations and the time spent in each Update phase.
+func Stats(s *Sandbox) inspect.Stats {
+	return s.internal.Stats()
+}
+
+// Instrument registers an instrumentation that receives the statistics at the end of each Update
+// (see the instrument package for expvar and log/slog adapters).
+func Instrument(s *Sandbox, instrumentation inspect.Instrumentation) {
+	s.internal.Instrument(instrumentation)
+}
@@ -0,0 +1,27 @@
+package snapshot
+
+import "errors"
+
+// Format defines the encoding used when saving a sandbox.
+type Format byte
+
+// Snapshot formats.
+const (
+	JSON   Format = iota // Human-readable JSON document
+	Binary               // Compact binary columns with a versioned schema
+)
+
+// Id identifies a checkpoint recorded as a delta baseline.
+type Id uint64
+
+// None is the empty baseline, a delta from None contains the full state.
+const None Id = 0
+
+// Snapshot errors.
+var (
+	ErrUnknownFormat    = errors.New("snapshot: unknown format")
+	ErrUnknownComponent = errors.New("snapshot: unknown component")
+	ErrInvalidSnapshot  = errors.New("snapshot: invalid snapshot")
+	ErrSchemaMismatch   = errors.New("snapshot: component layout changed")
+	ErrUnknownBaseline  = errors.New("snapshot: unknown baseline")
+)
@@ -0,0 +1,378 @@
+// Package spatial provides a uniform grid index over a position component, for proximity queries.
+package spatial
+
+import (
+	"cmp"
+	"container/heap"
+	"math"
+	"slices"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+)
+
+// Point is a 2D position.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// finite returns true if both coordinates are finite.
+func (p Point) finite() bool {
+	return !math.IsNaN(p.X) && !math.IsInf(p.X, 0) && !math.IsNaN(p.Y) && !math.IsInf(p.Y, 0)
+}
+
+// cell is the coordinate of a grid cell.
+type cell struct {
+	x int64
+	y int64
+}
+
+// entry is the indexed position of an entity, along with its cell and slot within the cell.
+type entry struct {
+	point Point
+	cell  cell
+	slot  int
+}
+
+// candidate is an entity found by a nearest query, along with its squared distance.
+type candidate struct {
+	entityId entity.Id
+	distance float64
+}
+
+// compare orders candidates by distance, then by ID.
+func (c candidate) compare(other candidate) int {
+	return cmp.Or(cmp.Compare(c.distance, other.distance), cmp.Compare(c.entityId, other.entityId))
+}
+
+// farthest is a max-heap of candidates, holding the closest ones found so far (the farthest of them on top).
+type farthest []candidate
+
+func (h farthest) Len() int           { return len(h) }
+func (h farthest) Less(i, j int) bool { return h[i].compare(h[j]) > 0 }
+func (h farthest) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *farthest) Push(value any)    { *h = append(*h, value.(candidate)) }
+func (h *farthest) Pop() any {
+	last := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return last
+}
+
+// offer keeps the candidate if fewer than count candidates were found, or if it is closer than the farthest one.
+func (h *farthest) offer(found candidate, count int) {
+	if len(*h) < count {
+		heap.Push(h, found)
+	} else if found.compare((*h)[0]) < 0 {
+		(*h)[0] = found
+		heap.Fix(h, 0)
+	}
+}
+
+// Grid is a spatial hash of the entities linked to a position component, updated at the end of each Update
+// as the component is linked, unlinked or marked changed. Queries return entity IDs in a deterministic order.
+// Entities at non-finite positions (NaN or infinite coordinates) aren't indexed.
+type Grid struct {
+	cellSize    float64
+	cells       map[cell][]entity.Id
+	entries     map[entity.Id]entry
+	observer    filter.Observer
+	min         cell // Bounds of the occupied cells
+	max         cell
+	boundsStale bool // Set once a cell on the bounds is emptied (the bounds are recomputed by the next query)
+}
+
+// New creates a grid with the given cell size (pick about the typical query radius), indexing the entities
+// linked to component T at the position returned by the extractor. Panics if the cell size isn't positive.
+func New[T component.Component](sb *sandbox.Sandbox, cellSize float64, position func(*T) Point) *Grid {
+	if cellSize <= 0 || math.IsInf(cellSize, 1) {
+		panic("spatial: cell size must be positive and finite")
+	}
+	grid := &Grid{
+		cellSize: cellSize,
+		cells:    make(map[cell][]entity.Id),
+		entries:  make(map[entity.Id]entry),
+	}
+	grid.observer = sandbox.Observe(sb, func(entityId entity.Id, instance *T) {
+		grid.set(entityId, position(instance))
+	}, grid.remove)
+	return grid
+}
+
+// Release stops updating the grid. The grid must not be used afterward (calling Release again has no effect).
+func (g *Grid) Release() {
+	g.observer.Release()
+}
+
+// Len returns the number of indexed entities.
+func (g *Grid) Len() int {
+	return len(g.entries)
+}
+
+// Position returns the indexed position of the entity, or false if it isn't indexed.
+func (g *Grid) Position(entityId entity.Id) (Point, bool) {
+	indexed, ok := g.entries[entityId]
+	return indexed.point, ok
+}
+
+// QueryAABB returns the entities inside the axis-aligned box (bounds included), in ascending order.
+func (g *Grid) QueryAABB(low, high Point) []entity.Id {
+	entityIds := make([]entity.Id, 0)
+	g.forEachCandidate(low, high, func(entityId entity.Id, point Point) {
+		if point.X >= low.X && point.X <= high.X && point.Y >= low.Y && point.Y <= high.Y {
+			entityIds = append(entityIds, entityId)
+		}
+	})
+	slices.Sort(entityIds)
+	return entityIds
+}
+
+// QueryRadius returns the entities within the radius of the center (bounds included), in ascending order.
+func (g *Grid) QueryRadius(center Point, radius float64) []entity.Id {
+	entityIds := make([]entity.Id, 0)
+	low, high := Point{X: center.X - radius, Y: center.Y - radius}, Point{X: center.X + radius, Y: center.Y + radius}
+	g.forEachCandidate(low, high, func(entityId entity.Id, point Point) {
+		if distance(center, point) <= radius*radius {
+			entityIds = append(entityIds, entityId)
+		}
+	})
+	slices.Sort(entityIds)
+	return entityIds
+}
+
+// Nearest returns up to count entities closest to the center, by increasing distance (equal distances by ID).
+// Returns nil if the center isn't finite.
+func (g *Grid) Nearest(center Point, count int) []entity.Id {
+	if count <= 0 || len(g.entries) == 0 || !center.finite() {
+		return nil
+	}
+	g.refreshBounds()
+	closest := make(farthest, 0, min(count, len(g.entries)))
+	offer := func(entityId entity.Id, point Point) {
+		closest.offer(candidate{entityId: entityId, distance: distance(center, point)}, count)
+	}
+
+	// Search the rings of cells around the center cell (clipped to the occupied bounds, starting from the first ring reaching them),
+	// until the found entities are closer than any unsearched cell. Cells outside the previous ring are at least ring - 1 cell sizes away.
+	// The rings visit at most as many cells as are occupied, otherwise all the entities are scanned instead.
+	origin := g.cellOf(center)
+	budget := len(g.cells)
+	for ring := max(g.min.x-origin.x, origin.x-g.max.x, g.min.y-origin.y, origin.y-g.max.y, 0); !g.covers(origin, ring-1); ring++ {
+		if reach := float64(ring-1) * g.cellSize; ring > 0 && len(closest) == count && closest[0].distance <= reach*reach {
+			break
+		}
+		cells := g.ringCells(origin, ring)
+		if cells > budget {
+			closest = closest[:0]
+			for entityId, indexed := range g.entries {
+				offer(entityId, indexed.point)
+			}
+			break
+		}
+		budget -= cells
+		g.forEachInRing(origin, ring, func(current cell) {
+			g.visit(g.cells[current], offer)
+		})
+	}
+
+	slices.SortFunc(closest, candidate.compare)
+	entityIds := make([]entity.Id, len(closest))
+	for index, found := range closest {
+		entityIds[index] = found.entityId
+	}
+	return entityIds
+}
+
+// Within keeps the entities present in the view (e.g. a filter, to intersect query results with it), filtering the slice in place.
+func Within(entityIds []entity.Id, view entity.MaskView) []entity.Id {
+	mask := view.EntityMask()
+	return slices.DeleteFunc(entityIds, func(entityId entity.Id) bool {
+		return !mask.Test(entityId)
+	})
+}
+
+// set indexes the entity at the given position, moving it between cells if needed (non-finite positions aren't indexed).
+func (g *Grid) set(entityId entity.Id, point Point) {
+	if !point.finite() {
+		g.remove(entityId)
+		return
+	}
+	target := g.cellOf(point)
+	if indexed, ok := g.entries[entityId]; ok {
+		if indexed.cell == target {
+			indexed.point = point
+			g.entries[entityId] = indexed
+			return
+		}
+		g.remove(entityId)
+	}
+
+	g.entries[entityId] = entry{point: point, cell: target, slot: len(g.cells[target])}
+	g.cells[target] = append(g.cells[target], entityId)
+	g.extend(target)
+}
+
+// remove drops the entity from the index (the last entity of its cell takes its slot).
+func (g *Grid) remove(entityId entity.Id) {
+	indexed, ok := g.entries[entityId]
+	if !ok {
+		return
+	}
+	delete(g.entries, entityId)
+
+	cellEntities := g.cells[indexed.cell]
+	last := len(cellEntities) - 1
+	if indexed.slot != last {
+		moved := cellEntities[last]
+		cellEntities[indexed.slot] = moved
+		movedEntry := g.entries[moved]
+		movedEntry.slot = indexed.slot
+		g.entries[moved] = movedEntry
+	}
+	if last > 0 {
+		g.cells[indexed.cell] = cellEntities[:last]
+		return
+	}
+
+	// If the emptied cell was on the bounds, the bounds may shrink
+	delete(g.cells, indexed.cell)
+	if indexed.cell.x == g.min.x || indexed.cell.x == g.max.x || indexed.cell.y == g.min.y || indexed.cell.y == g.max.y {
+		g.boundsStale = true
+	}
+}
+
+// maxCell bounds the cell coordinates, so that cell arithmetic can't overflow (far positions share the outermost cells).
+const maxCell = 1 << 52
+
+// cellOf returns the cell containing the point.
+func (g *Grid) cellOf(point Point) cell {
+	return cell{x: g.coordinateOf(point.X), y: g.coordinateOf(point.Y)}
+}
+
+// coordinateOf returns the cell coordinate containing the value, clamped to maxCell.
+func (g *Grid) coordinateOf(value float64) int64 {
+	return int64(math.Max(-maxCell, math.Min(maxCell, math.Floor(value/g.cellSize))))
+}
+
+// extend grows the occupied bounds to include the cell.
+func (g *Grid) extend(target cell) {
+	if len(g.cells) == 1 {
+		g.min, g.max = target, target
+		g.boundsStale = false
+		return
+	}
+	g.min = cell{x: min(g.min.x, target.x), y: min(g.min.y, target.y)}
+	g.max = cell{x: max(g.max.x, target.x), y: max(g.max.y, target.y)}
+}
+
+// refreshBounds recomputes the occupied bounds if a cell on them was emptied.
+func (g *Grid) refreshBounds() {
+	if !g.boundsStale {
+		return
+	}
+	g.boundsStale = false
+	first := true
+	for current := range g.cells {
+		if first {
+			g.min, g.max = current, current
+			first = false
+			continue
+		}
+		g.min = cell{x: min(g.min.x, current.x), y: min(g.min.y, current.y)}
+		g.max = cell{x: max(g.max.x, current.x), y: max(g.max.y, current.y)}
+	}
+}
+
+// covers returns true if the square of cells within the ring around the origin contains all the occupied bounds.
+func (g *Grid) covers(origin cell, ring int64) bool {
+	return origin.x-ring <= g.min.x && origin.y-ring <= g.min.y && origin.x+ring >= g.max.x && origin.y+ring >= g.max.y
+}
+
+// forEachCandidate calls the function for the entities in the cells overlapping the box.
+func (g *Grid) forEachCandidate(low, high Point, function func(entity.Id, Point)) {
+	if len(g.entries) == 0 || !low.finite() || !high.finite() || low.X > high.X || low.Y > high.Y {
+		return
+	}
+	g.refreshBounds()
+
+	// Clip the box cells to the occupied bounds
+	from, to := g.cellOf(low), g.cellOf(high)
+	from = cell{x: max(from.x, g.min.x), y: max(from.y, g.min.y)}
+	to = cell{x: min(to.x, g.max.x), y: min(to.y, g.max.y)}
+	if from.x > to.x || from.y > to.y {
+		return
+	}
+
+	// If the box covers more cells than are occupied, visit the occupied cells instead
+	if float64(to.x-from.x+1)*float64(to.y-from.y+1) > float64(len(g.cells)) {
+		for current, cellEntities := range g.cells {
+			if current.x >= from.x && current.x <= to.x && current.y >= from.y && current.y <= to.y {
+				g.visit(cellEntities, function)
+			}
+		}
+		return
+	}
+
+	for x := from.x; x <= to.x; x++ {
+		for y := from.y; y <= to.y; y++ {
+			g.visit(g.cells[cell{x: x, y: y}], function)
+		}
+	}
+}
+
+// ringCells returns the number of cells at the given Chebyshev distance from the origin, within the occupied bounds.
+func (g *Grid) ringCells(origin cell, ring int64) int {
+	return int(g.squareCells(origin, ring) - g.squareCells(origin, ring-1))
+}
+
+// squareCells returns the number of cells within the given Chebyshev distance from the origin, within the occupied bounds.
+func (g *Grid) squareCells(origin cell, ring int64) int64 {
+	if ring < 0 {
+		return 0
+	}
+	width := min(origin.x+ring, g.max.x) - max(origin.x-ring, g.min.x) + 1
+	height := min(origin.y+ring, g.max.y) - max(origin.y-ring, g.min.y) + 1
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+	return width * height
+}
+
+// forEachInRing calls the function for the cells at the given Chebyshev distance from the origin, within the occupied bounds.
+func (g *Grid) forEachInRing(origin cell, ring int64, function func(cell)) {
+	// Rows at the top and bottom of the ring
+	for _, y := range []int64{origin.y - ring, origin.y + ring} {
+		if y >= g.min.y && y <= g.max.y {
+			for x := max(origin.x-ring, g.min.x); x <= min(origin.x+ring, g.max.x); x++ {
+				function(cell{x: x, y: y})
+			}
+		}
+		if ring == 0 {
+			return
+		}
+	}
+
+	// Columns on the left and right of the ring (without the corners)
+	for _, x := range []int64{origin.x - ring, origin.x + ring} {
+		if x >= g.min.x && x <= g.max.x {
+			for y := max(origin.y-ring+1, g.min.y); y <= min(origin.y+ring-1, g.max.y); y++ {
+				function(cell{x: x, y: y})
+			}
+		}
+	}
+}
+
+// visit calls the function for the entities of a cell.
+func (g *Grid) visit(cellEntities []entity.Id, function func(entity.Id, Point)) {
+	for _, entityId := range cellEntities {
+		function(entityId, g.entries[entityId].point)
+	}
+}
+
+// distance returns the squared distance between 2 points.
+func distance(a, b Point) float64 {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	return dx*dx + dy*dy
+}
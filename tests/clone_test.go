@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCloneSuite(t *testing.T) {
+	suite.Run(t, &CloneTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &CloneTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &CloneTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type CloneTestSuite struct {
+	sandboxSuite
+	mode            options.Mode
+	poolSize        uint
+	positionLinker  component.Linker[position]
+	followerLinker  component.Linker[follower]
+	inventoryLinker component.Linker[inventory]
+	renderedLinker  component.TagLinker
+}
+
+func (suite *CloneTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.followerLinker = sandbox.ComponentLinker[follower](suite.sandbox)
+	suite.inventoryLinker = sandbox.ComponentLinker[inventory](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+}
+
+func (suite *CloneTestSuite) TestClone_CopiesComponentsAndTags() {
+	sourceId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(sourceId).X = 5
+	suite.followerLinker.Link(sourceId).Escorts = []component.Ref{component.RefTo(sourceId)}
+	suite.inventoryLinker.Link(sourceId).Items = []string{"sword"}
+	suite.renderedLinker.Link(sourceId)
+	otherId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(otherId)
+	sandbox.Update(suite.sandbox)
+
+	cloneId, ok := sandbox.CloneEntity(suite.sandbox, sourceId)
+	assert.True(suite.T(), ok)
+	assert.NotEqual(suite.T(), sourceId, cloneId)
+	suite.assertEntity(cloneId, entityNotLinkedMsg, cloneId)
+	assert.Equal(suite.T(), position{X: 5}, *suite.positionLinker.Get(cloneId), componentValueMsg, positionComponent, cloneId)
+	suite.assertComponent(suite.renderedLinker, cloneId, componentNotLinkedMsg, renderedComponent, cloneId)
+
+	// Components are copied shallowly, unless they implement component.Cloner
+	suite.followerLinker.Get(cloneId).Escorts[0] = component.RefTo(cloneId)
+	assert.Equal(suite.T(), component.RefTo(cloneId), suite.followerLinker.Get(sourceId).Escorts[0])
+	suite.inventoryLinker.Get(cloneId).Items[0] = "bow"
+	assert.Equal(suite.T(), []string{"sword"}, suite.inventoryLinker.Get(sourceId).Items)
+	assert.Equal(suite.T(), []string{"bow"}, suite.inventoryLinker.Get(cloneId).Items)
+}
+
+func (suite *CloneTestSuite) TestClone_FiresHooksAndUpdatesFilters() {
+	componentLinks := 0
+	tagLinks := 0
+	suite.positionLinker.SetLinkHook(func(*position) {
+		componentLinks++
+	})
+	suite.renderedLinker.SetLinkHook(func() {
+		tagLinks++
+	})
+	view := sandbox.Filter(suite.sandbox, filter.Match[position](), filter.MatchTags(renderedComponent))
+
+	sourceId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(sourceId)
+	suite.renderedLinker.Link(sourceId)
+	sandbox.Update(suite.sandbox)
+
+	for range numRemoves {
+		sandbox.CloneEntity(suite.sandbox, sourceId)
+	}
+	assert.Equal(suite.T(), 1+numRemoves, tagLinks)
+	assert.Equal(suite.T(), 1, componentLinks)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), 1+numRemoves, componentLinks)
+	assert.Len(suite.T(), view.EntityIds(), 1+numRemoves, filterIncorrectNumEntitiesMsg)
+}
+
+func (suite *CloneTestSuite) TestClone_MissingSource() {
+	_, ok := sandbox.CloneEntity(suite.sandbox, numEntities)
+	assert.False(suite.T(), ok)
+	assert.Empty(suite.T(), sandbox.Entities(suite.sandbox))
+}
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/debug"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDebugSuite(t *testing.T) {
+	suite.Run(t, &DebugTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &DebugTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &DebugTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type DebugTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	healthLinker   component.Linker[health]
+	renderedLinker component.TagLinker
+	handler        *debug.Handler
+	server         *httptest.Server
+}
+
+func (suite *DebugTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.healthLinker = sandbox.ComponentLinker[health](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+	sandbox.Filter(suite.sandbox, filter.Match[position]())
+	suite.handler = debug.NewHandler(suite.sandbox)
+	suite.handler.MaxWait = 0 // Requests are served the entities rendered at the end of the previous Update
+	suite.server = httptest.NewServer(http.StripPrefix("/debug", suite.handler))
+}
+
+func (suite *DebugTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *DebugTestSuite) fetchState() debug.State {
+	response, err := http.Get(suite.server.URL + "/debug" + debug.StatePath)
+	suite.Require().NoError(err)
+	return suite.decodeState(response)
+}
+
+func (suite *DebugTestSuite) decodeState(response *http.Response) debug.State {
+	defer response.Body.Close()
+	suite.Require().Equal(http.StatusOK, response.StatusCode)
+	suite.Require().Equal("application/json", response.Header.Get("Content-Type"))
+
+	var state debug.State
+	suite.Require().NoError(json.NewDecoder(response.Body).Decode(&state))
+	return state
+}
+
+func (suite *DebugTestSuite) TestDebug_ServesStateAtUpdateBoundaries() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	*suite.positionLinker.Link(entityId) = position{X: 1, Y: 2}
+	suite.healthLinker.Link(entityId).value = 10
+	suite.renderedLinker.Link(entityId)
+	removedId := sandbox.LinkEntity(suite.sandbox)
+
+	// Changes are only visible after Update (the request has the entities rendered at its end)
+	state := suite.fetchState()
+	assert.Equal(suite.T(), uint64(0), state.Stats.Frame)
+	assert.Empty(suite.T(), state.Entities)
+
+	sandbox.Update(suite.sandbox)
+	state = suite.fetchState()
+	assert.Equal(suite.T(), uint64(1), state.Stats.Frame)
+	assert.Equal(suite.T(), uint(2), state.Stats.EntitiesLinked)
+	assert.Equal(suite.T(), uint64(1), state.EntitiesFrame)
+	assert.Len(suite.T(), state.Components, 3)
+	if assert.Len(suite.T(), state.Filters, 1) {
+		assert.Equal(suite.T(), uint(1), state.Filters[0].Entities)
+	}
+	if assert.Len(suite.T(), state.Entities, 2) {
+		assert.Equal(suite.T(), entityId, state.Entities[0].Id)
+		assert.Equal(suite.T(), map[string]any{
+			testsPath + "position": map[string]any{"X": 1.0, "Y": 2.0},
+			testsPath + "health":   map[string]any{"value": 10.0},
+		}, state.Entities[0].Components)
+		assert.Equal(suite.T(), []string{renderedComponent}, state.Entities[0].Tags)
+		assert.Empty(suite.T(), state.Entities[1].Components)
+	}
+
+	sandbox.UnlinkEntity(suite.sandbox, removedId)
+	sandbox.Update(suite.sandbox)
+	state = suite.fetchState()
+	assert.Equal(suite.T(), uint64(2), state.Stats.Frame)
+	assert.Equal(suite.T(), uint(0), state.Stats.EntitiesLinked)
+	assert.Equal(suite.T(), uint(1), state.Stats.EntitiesUnlinked)
+	assert.Len(suite.T(), state.Entities, 1)
+}
+
+func (suite *DebugTestSuite) TestDebug_RendersEntitiesOnRequest() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(entityId)
+
+	// Without requests, only the stats, components and filters are captured
+	sandbox.Update(suite.sandbox)
+	state := suite.handler.State()
+	assert.Equal(suite.T(), uint64(1), state.Stats.Frame)
+	assert.Equal(suite.T(), uint64(0), state.EntitiesFrame)
+	assert.Empty(suite.T(), state.Entities)
+
+	// A request waits for the next Update to render the entities
+	suite.handler.MaxWait = time.Minute
+	responses := make(chan *http.Response)
+	go func() {
+		response, err := http.Get(suite.server.URL + "/debug" + debug.StatePath)
+		if err != nil {
+			suite.T().Error(err)
+		}
+		responses <- response
+	}()
+	for {
+		sandbox.Update(suite.sandbox)
+		select {
+		case response := <-responses:
+			suite.Require().NotNil(response)
+			state := suite.decodeState(response)
+			assert.Equal(suite.T(), state.Stats.Frame, state.EntitiesFrame)
+			if assert.Len(suite.T(), state.Entities, 1) {
+				assert.Equal(suite.T(), entityId, state.Entities[0].Id)
+			}
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (suite *DebugTestSuite) TestDebug_ServesPage() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(entityId)
+	sandbox.Update(suite.sandbox)
+
+	recorder := httptest.NewRecorder()
+	suite.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Contains(suite.T(), recorder.Header().Get("Content-Type"), "text/html")
+	assert.Contains(suite.T(), recorder.Body.String(), testsPath+"position")
+	assert.Contains(suite.T(), recorder.Body.String(), "Match("+testsPath+"position)")
+
+	// The JSON link is absolute, so it works with or without a trailing slash on the mount path
+	for _, path := range []string{"/debug", "/debug/"} {
+		response, err := http.Get(suite.server.URL + path)
+		suite.Require().NoError(err)
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		suite.Require().NoError(err)
+		assert.Contains(suite.T(), string(body), `href="/debug`+debug.StatePath+`"`, path)
+	}
+
+	recorder = httptest.NewRecorder()
+	suite.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(suite.T(), http.StatusMethodNotAllowed, recorder.Code)
+}
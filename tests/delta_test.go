@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDeltaSuite(t *testing.T) {
+	suite.Run(t, &DeltaTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &DeltaTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &DeltaTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type DeltaTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	mirror         *sandbox.Sandbox
+	positionLinker component.Linker[position]
+	velocityLinker component.Linker[velocity]
+	renderedLinker component.TagLinker
+}
+
+func (suite *DeltaTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.mirror = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.velocityLinker = sandbox.ComponentLinker[velocity](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+	sandbox.ComponentLinker[velocity](suite.mirror)
+	sandbox.ComponentLinker[position](suite.mirror)
+
+	for index := range numEntities {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId).X = float64(index)
+		if index%2 == 0 {
+			suite.velocityLinker.Link(entityId).Y = float64(index)
+		}
+		if index%3 == 0 {
+			suite.renderedLinker.Link(entityId)
+		}
+	}
+	sandbox.Update(suite.sandbox)
+}
+
+// replicate sends the delta from the baseline over a pipe, and returns the number of bytes sent.
+func (suite *DeltaTestSuite) replicate(baseline snapshot.Id) int {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(sandbox.SaveDelta(suite.sandbox, writer, baseline))
+	}()
+
+	counter := &countingReader{reader: reader}
+	assert.NoError(suite.T(), sandbox.ApplyDelta(suite.mirror, counter))
+	_, _ = io.Copy(io.Discard, reader)
+	return counter.count
+}
+
+func (suite *DeltaTestSuite) assertMirrored() {
+	positionLinker := sandbox.ComponentLinker[position](suite.mirror)
+	velocityLinker := sandbox.ComponentLinker[velocity](suite.mirror)
+	renderedLinker := sandbox.TagLinker(suite.mirror, renderedComponent)
+
+	for index := range numEntities + numRemoves {
+		entityId := entity.Id(index)
+		assert.Equal(suite.T(), sandbox.IsEntityLinked(suite.sandbox, entityId), sandbox.IsEntityLinked(suite.mirror, entityId), entityNotLinkedMsg, index)
+		assert.Equal(suite.T(), suite.positionLinker.Has(entityId), positionLinker.Has(entityId), componentNotLinkedMsg, positionComponent, index)
+		assert.Equal(suite.T(), suite.velocityLinker.Has(entityId), velocityLinker.Has(entityId), componentNotLinkedMsg, velocityComponent, index)
+		assert.Equal(suite.T(), suite.renderedLinker.Has(entityId), renderedLinker.Has(entityId), componentNotLinkedMsg, renderedComponent, index)
+		if positionLinker.Has(entityId) {
+			assert.Equal(suite.T(), *suite.positionLinker.Get(entityId), *positionLinker.Get(entityId), componentValueMsg, positionComponent, index)
+		}
+		if velocityLinker.Has(entityId) {
+			assert.Equal(suite.T(), *suite.velocityLinker.Get(entityId), *velocityLinker.Get(entityId), componentValueMsg, velocityComponent, index)
+		}
+	}
+}
+
+func (suite *DeltaTestSuite) TestDelta_Replication() {
+	fullSize := suite.replicate(snapshot.None)
+	baseline := sandbox.Checkpoint(suite.sandbox)
+	suite.assertMirrored()
+
+	// Change values, unlink components and tags, remove and create entities
+	for index := 0; index < numEntities; index += 7 {
+		suite.positionLinker.Get(entity.Id(index)).Y = 1
+		suite.positionLinker.MarkChanged(entity.Id(index))
+	}
+	for index := 0; index < numEntities; index += 10 {
+		suite.velocityLinker.Unlink(entity.Id(index))
+		suite.renderedLinker.Unlink(entity.Id(index + 3))
+		suite.renderedLinker.Link(entity.Id(index + 1))
+	}
+	for _, entityId := range getRandomIds(numEntities, numRemoves) {
+		sandbox.UnlinkEntity(suite.sandbox, entityId)
+	}
+	sandbox.Update(suite.sandbox)
+	for index := range numRemoves * 2 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.velocityLinker.Link(entityId).X = float64(index)
+	}
+	sandbox.Update(suite.sandbox)
+
+	deltaSize := suite.replicate(baseline)
+	suite.assertMirrored()
+	assert.Less(suite.T(), deltaSize, fullSize)
+
+	// Without changes, nothing but the header is sent
+	sandbox.ReleaseCheckpoint(suite.sandbox, baseline)
+	baseline = sandbox.Checkpoint(suite.sandbox)
+	assert.Less(suite.T(), suite.replicate(baseline), 16)
+	suite.assertMirrored()
+}
+
+func (suite *DeltaTestSuite) TestDelta_UnmarkedChangesNotSent() {
+	suite.replicate(snapshot.None)
+	baseline := sandbox.Checkpoint(suite.sandbox)
+
+	suite.positionLinker.Get(1).X = -1
+	sandbox.Update(suite.sandbox)
+	suite.replicate(baseline)
+	assert.Equal(suite.T(), float64(1), sandbox.ComponentLinker[position](suite.mirror).Get(1).X)
+
+	suite.positionLinker.MarkChanged(1)
+	sandbox.Update(suite.sandbox)
+	suite.replicate(baseline)
+	assert.Equal(suite.T(), float64(-1), sandbox.ComponentLinker[position](suite.mirror).Get(1).X)
+}
+
+func (suite *DeltaTestSuite) TestDelta_UnknownBaseline() {
+	var buffer bytes.Buffer
+	assert.ErrorIs(suite.T(), sandbox.SaveDelta(suite.sandbox, &buffer, 42), snapshot.ErrUnknownBaseline)
+
+	baseline := sandbox.Checkpoint(suite.sandbox)
+	sandbox.ReleaseCheckpoint(suite.sandbox, baseline)
+	assert.ErrorIs(suite.T(), sandbox.SaveDelta(suite.sandbox, &buffer, baseline), snapshot.ErrUnknownBaseline)
+}
+
+func (suite *DeltaTestSuite) TestDelta_OversizedLengths() {
+	header := append([]byte("SDCD"), 1, 0, 0, 0)
+	huge := binary.AppendUvarint(nil, 1<<32)
+	for name, encoded := range map[string][]byte{
+		"column count": append(slices.Clone(header), huge...),
+		"name length":  append(append(slices.Clone(header), 1), huge...),
+		"sparse count": append([]byte("SDCD"), append([]byte{1, 0}, huge...)...),
+		"entity id":    append([]byte("SDCD"), append([]byte{1, 0, 1}, huge...)...),
+	} {
+		// Lengths beyond the input fail without allocating them
+		assert.ErrorIs(suite.T(), sandbox.ApplyDelta(suite.mirror, bytes.NewReader(encoded)), snapshot.ErrInvalidSnapshot, name)
+	}
+}
+
+func (suite *DeltaTestSuite) TestDelta_ApplyRunsUpdateHooks() {
+	frames := 0
+	sandbox.OnUpdate(suite.mirror, func() {
+		frames++
+	})
+	suite.replicate(snapshot.None)
+	assert.Equal(suite.T(), 1, frames)
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	reader io.Reader
+	count  int
+}
+
+func (c *countingReader) Read(data []byte) (int, error) {
+	read, err := c.reader.Read(data)
+	c.count += read
+	return read, err
+}
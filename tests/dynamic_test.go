@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/inspect"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var healthFields = []component.Field{
+	{Name: "current", Kind: component.IntField},
+	{Name: "regen", Kind: component.FloatField},
+	{Name: "label", Kind: component.StringField},
+	{Name: "boss", Kind: component.BoolField},
+	{Name: "attacker", Kind: component.RefField},
+}
+
+func TestDynamicSuite(t *testing.T) {
+	suite.Run(t, &DynamicTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &DynamicTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &DynamicTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type DynamicTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	healthLinker   component.DynamicLinker
+}
+
+func (suite *DynamicTestSuite) SetupTest() {
+	suite.sandbox = suite.newSandbox()
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.healthLinker = suite.registerHealth(suite.sandbox)
+}
+
+func (suite *DynamicTestSuite) newSandbox() *sandbox.Sandbox {
+	return sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+}
+
+func (suite *DynamicTestSuite) registerHealth(s *sandbox.Sandbox) component.DynamicLinker {
+	linker, err := sandbox.DynamicLinker(s, "health", healthFields...)
+	suite.Require().NoError(err)
+	return linker
+}
+
+func (suite *DynamicTestSuite) TestDynamic_Fields() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	record := suite.healthLinker.Link(entityId)
+	assert.Equal(suite.T(), int64(0), record.Int("current"))
+	assert.Equal(suite.T(), "health", record.Schema().Name())
+
+	record.SetInt("current", 10)
+	record.SetFloat("regen", 0.5)
+	record.SetString("label", "orc")
+	record.SetBool("boss", true)
+	record.SetRef("attacker", component.RefTo(3))
+	assert.Equal(suite.T(), map[string]any{
+		"current": int64(10), "regen": 0.5, "label": "orc", "boss": true, "attacker": component.RefTo(3),
+	}, suite.healthLinker.Get(entityId).Map())
+
+	// Untyped access converts integers and reports unknown fields and kind mismatches
+	assert.NoError(suite.T(), record.Set("current", 12))
+	assert.NoError(suite.T(), record.Set("regen", 2))
+	assert.ErrorIs(suite.T(), record.Set("mana", 1), component.ErrUnknownField)
+	assert.ErrorIs(suite.T(), record.Set("label", 1), component.ErrFieldKind)
+	value, err := record.Get("regen")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2.0, value)
+	assert.Equal(suite.T(), int64(12), record.Int("current"))
+	assert.Panics(suite.T(), func() { record.Float("current") })
+
+	// Relinked records start from the zero values, even when storage is reused
+	suite.healthLinker.Unlink(entityId)
+	sandbox.Update(suite.sandbox)
+	record = suite.healthLinker.Link(entityId)
+	assert.Equal(suite.T(), int64(0), record.Int("current"))
+	assert.Equal(suite.T(), "", record.String("label"))
+}
+
+func (suite *DynamicTestSuite) TestDynamic_FailedSetKeepsValue() {
+	record := suite.healthLinker.Link(sandbox.LinkEntity(suite.sandbox))
+	record.SetInt("current", 10)
+	record.SetFloat("regen", 0.5)
+	record.SetString("label", "orc")
+	record.SetBool("boss", true)
+	record.SetRef("attacker", component.RefTo(3))
+
+	// Failed conversions leave the fields unchanged
+	assert.ErrorIs(suite.T(), record.Set("current", "12"), component.ErrFieldKind)
+	assert.ErrorIs(suite.T(), record.Set("current", uint64(math.MaxUint64)), component.ErrFieldKind)
+	assert.ErrorIs(suite.T(), record.Set("regen", "2"), component.ErrFieldKind)
+	assert.ErrorIs(suite.T(), record.Set("label", 1), component.ErrFieldKind)
+	assert.ErrorIs(suite.T(), record.Set("boss", 1), component.ErrFieldKind)
+	assert.ErrorIs(suite.T(), record.Set("attacker", 1), component.ErrFieldKind)
+	assert.Equal(suite.T(), map[string]any{
+		"current": int64(10), "regen": 0.5, "label": "orc", "boss": true, "attacker": component.RefTo(3),
+	}, record.Map())
+}
+
+func (suite *DynamicTestSuite) TestDynamic_RelinkKeepsOtherRecords() {
+	// Compact storage moves the last record into the freed slot: records linked afterwards must not share its fields
+	first, second := sandbox.LinkEntity(suite.sandbox), sandbox.LinkEntity(suite.sandbox)
+	suite.healthLinker.Link(first).SetInt("current", 1)
+	suite.healthLinker.Link(second).SetInt("current", 2)
+	suite.healthLinker.Unlink(first)
+	sandbox.Update(suite.sandbox)
+
+	third := sandbox.LinkEntity(suite.sandbox)
+	suite.healthLinker.Link(third).SetInt("current", 3)
+	assert.Equal(suite.T(), int64(2), suite.healthLinker.Get(second).Int("current"))
+	assert.Equal(suite.T(), int64(3), suite.healthLinker.Get(third).Int("current"))
+}
+
+func (suite *DynamicTestSuite) TestDynamic_Registration() {
+	same, err := sandbox.DynamicLinker(suite.sandbox, "health", healthFields...)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.healthLinker.ComponentId(), same.ComponentId())
+
+	_, err = sandbox.DynamicLinker(suite.sandbox, "health", component.Field{Name: "current", Kind: component.FloatField})
+	assert.ErrorIs(suite.T(), err, component.ErrSchemaConflict)
+	_, err = sandbox.DynamicLinker(suite.sandbox, testsPath+"position")
+	assert.ErrorIs(suite.T(), err, component.ErrSchemaConflict)
+	_, err = sandbox.DynamicLinker(suite.sandbox, "mana", component.Field{Name: "value"}, component.Field{Name: "value"})
+	assert.ErrorIs(suite.T(), err, component.ErrInvalidSchema)
+	_, err = sandbox.DynamicLinker(suite.sandbox, "")
+	assert.ErrorIs(suite.T(), err, component.ErrInvalidSchema)
+}
+
+func (suite *DynamicTestSuite) TestDynamic_Filters() {
+	healthId := suite.healthLinker.ComponentId()
+	both := sandbox.Filter(suite.sandbox, filter.Match[position](), filter.MatchIds(healthId))
+	without := sandbox.Filter(suite.sandbox, filter.Match[position](), filter.ExcludeIds(healthId))
+
+	for index := range 6 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId)
+		if index%2 == 0 {
+			suite.healthLinker.Link(entityId)
+		}
+	}
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []uint{0, 2, 4}, both.EntityIds())
+	assert.Equal(suite.T(), []uint{1, 3, 5}, without.EntityIds())
+	assert.Equal(suite.T(), []uint{0, 2, 4}, sandbox.QueryOnce(suite.sandbox, filter.UnionIds(healthId)))
+
+	assert.PanicsWithError(suite.T(), "component: unknown component: 100", func() {
+		sandbox.QueryOnce(suite.sandbox, filter.MatchIds(100))
+	})
+}
+
+func (suite *DynamicTestSuite) TestDynamic_CloneIsDeep() {
+	sourceId := sandbox.LinkEntity(suite.sandbox)
+	suite.healthLinker.Link(sourceId).SetString("label", "orc")
+	sandbox.Update(suite.sandbox)
+
+	cloneId, _ := sandbox.CloneEntity(suite.sandbox, sourceId)
+	sandbox.Update(suite.sandbox)
+	suite.healthLinker.Get(cloneId).SetString("label", "goblin")
+	assert.Equal(suite.T(), "orc", suite.healthLinker.Get(sourceId).String("label"))
+	assert.Equal(suite.T(), "goblin", suite.healthLinker.Get(cloneId).String("label"))
+}
+
+func (suite *DynamicTestSuite) TestDynamic_Snapshot() {
+	for _, format := range []snapshot.Format{snapshot.Binary, snapshot.JSON} {
+		source := suite.newSandbox()
+		sourceLinker := suite.registerHealth(source)
+		first, second := sandbox.LinkEntity(source), sandbox.LinkEntity(source)
+		record := sourceLinker.Link(second)
+		record.SetInt("current", 7)
+		record.SetFloat("regen", 1.5)
+		record.SetString("label", "orc")
+		record.SetBool("boss", true)
+		record.SetRef("attacker", component.RefTo(first))
+		sandbox.Update(source)
+
+		var buffer bytes.Buffer
+		suite.Require().NoError(sandbox.Save(source, &buffer, format))
+
+		// The component is registered by name in the loaded sandbox (ids differ from the saved sandbox)
+		loaded := suite.newSandbox()
+		sandbox.ComponentLinker[position](loaded)
+		loadedLinker := suite.registerHealth(loaded)
+		suite.Require().NoError(sandbox.Load(loaded, bytes.NewReader(buffer.Bytes())))
+		assert.Equal(suite.T(), record.Map(), loadedLinker.Get(second).Map(), format)
+		assert.False(suite.T(), loadedLinker.Has(first), format)
+
+		// Loading into a sandbox with a different schema is a layout mismatch
+		changed := suite.newSandbox()
+		_, err := sandbox.DynamicLinker(changed, "health", healthFields[1:]...)
+		suite.Require().NoError(err)
+		if format == snapshot.Binary {
+			assert.ErrorIs(suite.T(), sandbox.Load(changed, bytes.NewReader(buffer.Bytes())), snapshot.ErrSchemaMismatch)
+		} else {
+			assert.ErrorIs(suite.T(), sandbox.Load(changed, bytes.NewReader(buffer.Bytes())), snapshot.ErrInvalidSnapshot)
+		}
+	}
+}
+
+func (suite *DynamicTestSuite) TestDynamic_Inspect() {
+	sandbox.LinkEntity(suite.sandbox)
+	suite.healthLinker.Link(0)
+	sandbox.Update(suite.sandbox)
+
+	for _, descriptor := range sandbox.Components(suite.sandbox) {
+		if descriptor.Name == "health" {
+			assert.Equal(suite.T(), inspect.ComponentKind, descriptor.Kind)
+			assert.Equal(suite.T(), uint(1), descriptor.Linked)
+			assert.Equal(suite.T(), healthFields, descriptor.Fields)
+			return
+		}
+	}
+	suite.Fail("dynamic component not described")
+}
@@ -0,0 +1,10 @@
+// Package fixtures provides a component and a typed tag whose short names ("fixtures.Position", "fixtures.Marker") collide with the beta package's.
+package fixtures
+
+// Position is a component with the same short type name as beta's.
+type Position struct {
+	X, Y float64
+}
+
+// Marker is a typed tag with the same short type name as beta's.
+type Marker struct{}
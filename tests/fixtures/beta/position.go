@@ -0,0 +1,10 @@
+// Package fixtures provides a component and a typed tag whose short names ("fixtures.Position", "fixtures.Marker") collide with the alpha package's.
+package fixtures
+
+// Position is a component with the same short type name as alpha's.
+type Position struct {
+	Z int
+}
+
+// Marker is a typed tag with the same short type name as alpha's.
+type Marker struct{}
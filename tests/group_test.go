@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestGroupSuite(t *testing.T) {
+	suite.Run(t, &GroupTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &GroupTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &GroupTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type GroupTestSuite struct {
+	sandboxSuite
+	mode        options.Mode
+	poolSize    uint
+	armorLinker component.Linker[armor]
+	view        filter.View
+	groups      filter.Groups[int]
+}
+
+func (suite *GroupTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.armorLinker = sandbox.ComponentLinker[armor](suite.sandbox)
+	suite.view = sandbox.Filter(suite.sandbox, filter.Match[armor]())
+	suite.groups = sandbox.GroupBy(suite.sandbox, suite.view, func(component *armor) int {
+		return component.value % 3
+	})
+}
+
+func (suite *GroupTestSuite) TestGroup_Partition() {
+	for index := range 300 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.armorLinker.Link(entityId).value = index
+	}
+	sandbox.Update(suite.sandbox)
+
+	assert.Equal(suite.T(), []int{0, 1, 2}, suite.groups.Keys())
+	suite.assertGroups()
+}
+
+func (suite *GroupTestSuite) TestGroup_Changes() {
+	first := sandbox.LinkEntity(suite.sandbox)
+	suite.armorLinker.Link(first).value = 1
+	second := sandbox.LinkEntity(suite.sandbox)
+	suite.armorLinker.Link(second).value = 2
+	sandbox.Update(suite.sandbox)
+
+	held := suite.groups.Group(0)
+	assert.Empty(suite.T(), held.EntityIds())
+
+	// Changed components move between groups on the next update
+	suite.armorLinker.Get(first).value = 3
+	suite.armorLinker.MarkChanged(first)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{first}, held.EntityIds())
+	assert.Equal(suite.T(), []int{2, 0}, suite.groups.Keys())
+
+	// Exiting entities leave their group
+	sandbox.UnlinkEntity(suite.sandbox, second)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []int{0}, suite.groups.Keys())
+	assert.Empty(suite.T(), suite.groups.Group(2).EntityIds())
+	suite.assertGroups()
+}
+
+func (suite *GroupTestSuite) TestGroup_EmptyGroupsRemoved() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.armorLinker.Link(entityId).value = 1
+	other := sandbox.LinkEntity(suite.sandbox)
+	suite.armorLinker.Link(other).value = 2
+	sandbox.Update(suite.sandbox)
+
+	// Keys without entities resolve to an empty view
+	for key := range 100 {
+		assert.Empty(suite.T(), suite.groups.Group(key+3).EntityIds())
+	}
+	held := suite.groups.Group(1)
+	assert.Equal(suite.T(), []entity.Id{entityId}, held.EntityIds())
+	var yielded entity.View
+	for key, group := range suite.groups.All() {
+		if key == 1 {
+			yielded = group
+		}
+	}
+
+	// Once empty, the group is removed, and the key moves to the end when it appears again
+	suite.armorLinker.Get(entityId).value = 0
+	suite.armorLinker.MarkChanged(entityId)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []int{2, 0}, suite.groups.Keys())
+	assert.Empty(suite.T(), held.EntityIds())
+	assert.Zero(suite.T(), held.EntityMask().Count())
+
+	suite.armorLinker.Get(entityId).value = 4
+	suite.armorLinker.MarkChanged(entityId)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []int{2, 1}, suite.groups.Keys())
+	assert.Equal(suite.T(), []entity.Id{entityId}, held.EntityIds())
+
+	// Groups yielded by All resolve their key like the ones returned by Group, so they follow the recreated group
+	assert.Equal(suite.T(), []entity.Id{entityId}, yielded.EntityIds())
+	suite.assertGroups()
+}
+
+func (suite *GroupTestSuite) TestGroup_Release() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.armorLinker.Link(entityId).value = 1
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []int{1}, suite.groups.Keys())
+
+	// Released groups are no longer updated
+	suite.groups.Release()
+	suite.groups.Release()
+	suite.armorLinker.Get(entityId).value = 2
+	suite.armorLinker.MarkChanged(entityId)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []int{1}, suite.groups.Keys())
+}
+
+func (suite *GroupTestSuite) assertGroups() {
+	var total uint
+	for key, group := range suite.groups.All() {
+		assert.NotEmpty(suite.T(), group.EntityIds())
+		for _, entityId := range group.EntityIds() {
+			assert.Equal(suite.T(), key, suite.armorLinker.Get(entityId).value%3)
+		}
+		total += group.EntityMask().Count()
+	}
+	assert.Equal(suite.T(), suite.view.EntityMask().Count(), total)
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	numChunkEntities = 100
+	chunkTag         = "CHUNK"
+)
+
+func TestImportSuite(t *testing.T) {
+	suite.Run(t, &ImportTestSuite{mode: options.Standard, format: snapshot.JSON})
+	suite.Run(t, &ImportTestSuite{mode: options.Pooled, format: snapshot.Binary})
+	suite.Run(t, &ImportTestSuite{mode: options.Compact, format: snapshot.Binary})
+}
+
+type ImportTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	format         snapshot.Format
+	chunk          []byte
+	positionLinker component.Linker[position]
+	followerLinker component.Linker[follower]
+	parentLinker   component.Linker[parent]
+}
+
+func (suite *ImportTestSuite) SetupTest() {
+	// Save a chunk whose entities reference each other (and an entity outside the chunk)
+	chunk := sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, 0)
+	positionLinker := sandbox.ComponentLinker[position](chunk)
+	followerLinker := sandbox.ComponentLinker[follower](chunk)
+	parentLinker := sandbox.ComponentLinker[parent](chunk)
+	chunkLinker := sandbox.TagLinker(chunk, chunkTag)
+	for index := range numChunkEntities {
+		entityId := sandbox.LinkEntity(chunk)
+		positionLinker.Link(entityId).X = float64(index)
+		followerLinker.Link(entityId).Target = component.RefTo((entityId + 1) % numChunkEntities)
+		followerLinker.Get(entityId).Escorts = []component.Ref{component.RefTo(0), component.RefTo(numEntities), {}}
+		parentLinker.Link(entityId).Id = 0
+		chunkLinker.Link(entityId)
+	}
+	sandbox.Update(chunk)
+
+	var buffer bytes.Buffer
+	assert.NoError(suite.T(), sandbox.Save(chunk, &buffer, suite.format))
+	suite.chunk = buffer.Bytes()
+
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, 0)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.followerLinker = sandbox.ComponentLinker[follower](suite.sandbox)
+	suite.parentLinker = sandbox.ComponentLinker[parent](suite.sandbox)
+	for range numChunkEntities / 2 {
+		suite.positionLinker.Link(sandbox.LinkEntity(suite.sandbox)).X = -1
+	}
+	sandbox.Update(suite.sandbox)
+}
+
+func (suite *ImportTestSuite) TestImport_RemapsEntities() {
+	view := sandbox.Filter(suite.sandbox, filter.Match[position](), filter.MatchTags(chunkTag))
+
+	first, err := sandbox.Import(suite.sandbox, bytes.NewReader(suite.chunk))
+	assert.NoError(suite.T(), err)
+	second, err := sandbox.Import(suite.sandbox, bytes.NewReader(suite.chunk))
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), first, numChunkEntities)
+	assert.Len(suite.T(), second, numChunkEntities)
+
+	sandbox.Update(suite.sandbox)
+	assert.Len(suite.T(), view.EntityIds(), numChunkEntities*2, filterIncorrectNumEntitiesMsg)
+
+	used := make(map[entity.Id]bool)
+	for _, newIds := range []map[entity.Id]entity.Id{first, second} {
+		for oldId, newId := range newIds {
+			assert.False(suite.T(), used[newId], "Entity id %d allocated twice", newId)
+			assert.GreaterOrEqual(suite.T(), newId, entity.Id(numChunkEntities/2), entityNotRecycledMsg, newId)
+			used[newId] = true
+
+			assert.Equal(suite.T(), float64(oldId), suite.positionLinker.Get(newId).X, componentValueMsg, positionComponent, newId)
+			follower := suite.followerLinker.Get(newId)
+			assert.Equal(suite.T(), component.RefTo(newIds[(oldId+1)%numChunkEntities]), follower.Target)
+			assert.Equal(suite.T(), []component.Ref{component.RefTo(newIds[0]), {}, {}}, follower.Escorts)
+			assert.Equal(suite.T(), newIds[0], suite.parentLinker.Get(newId).Id)
+		}
+	}
+
+	// Unloading a chunk leaves the other entities untouched
+	for _, newId := range first {
+		sandbox.UnlinkEntity(suite.sandbox, newId)
+	}
+	sandbox.Update(suite.sandbox)
+	assert.Len(suite.T(), view.EntityIds(), numChunkEntities, filterIncorrectNumEntitiesMsg)
+	for index := range numChunkEntities / 2 {
+		assert.Equal(suite.T(), float64(-1), suite.positionLinker.Get(entity.Id(index)).X, componentValueMsg, positionComponent, index)
+	}
+}
+
+func (suite *ImportTestSuite) TestImport_UnknownComponent() {
+	loaded := sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, 0)
+	sandbox.ComponentLinker[position](loaded)
+	_, err := sandbox.Import(loaded, bytes.NewReader(suite.chunk))
+	assert.ErrorIs(suite.T(), err, snapshot.ErrUnknownComponent)
+	assert.False(suite.T(), sandbox.IsEntityLinked(loaded, 0))
+}
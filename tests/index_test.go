@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestIndexSuite(t *testing.T) {
+	suite.Run(t, &IndexTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &IndexTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &IndexTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type IndexTestSuite struct {
+	sandboxSuite
+	mode       options.Mode
+	poolSize   uint
+	nameLinker component.Linker[name]
+	index      filter.Index[string]
+}
+
+func (suite *IndexTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.nameLinker = sandbox.ComponentLinker[name](suite.sandbox)
+	suite.index = sandbox.Index(suite.sandbox, func(component *name) string {
+		return component.value
+	})
+}
+
+func (suite *IndexTestSuite) TestIndex_Lookup() {
+	entityIds := make([]entity.Id, 100)
+	for index := range entityIds {
+		entityIds[index] = sandbox.LinkEntity(suite.sandbox)
+		suite.nameLinker.Link(entityIds[index]).value = string(rune('A'+index%26)) + string(rune('0'+index/26))
+	}
+	sandbox.Update(suite.sandbox)
+
+	for index, entityId := range entityIds {
+		owner, ok := suite.index.Lookup(suite.nameLinker.Get(entityId).value)
+		assert.True(suite.T(), ok, "key %d not indexed", index)
+		assert.Equal(suite.T(), entityId, owner)
+	}
+	assert.NoError(suite.T(), suite.index.Conflicts())
+
+	// Renamed entities are re-indexed once marked changed, removed entities are dropped
+	suite.nameLinker.Get(entityIds[0]).value = "renamed"
+	suite.nameLinker.MarkChanged(entityIds[0])
+	sandbox.UnlinkEntity(suite.sandbox, entityIds[1])
+	sandbox.Update(suite.sandbox)
+
+	owner, ok := suite.index.Lookup("renamed")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), entityIds[0], owner)
+	_, ok = suite.index.Lookup("A0")
+	assert.False(suite.T(), ok)
+	_, ok = suite.index.Lookup("B0")
+	assert.False(suite.T(), ok)
+}
+
+func (suite *IndexTestSuite) TestIndex_Conflicts() {
+	var conflicts [][2]entity.Id
+	suite.index.OnConflict(func(key string, owner, claimant entity.Id) {
+		assert.Equal(suite.T(), "player", key)
+		conflicts = append(conflicts, [2]entity.Id{owner, claimant})
+	})
+
+	first := sandbox.LinkEntity(suite.sandbox)
+	suite.nameLinker.Link(first).value = "player"
+	second := sandbox.LinkEntity(suite.sandbox)
+	suite.nameLinker.Link(second).value = "player"
+	sandbox.Update(suite.sandbox)
+
+	assert.Equal(suite.T(), [][2]entity.Id{{first, second}}, conflicts)
+	assert.ErrorIs(suite.T(), suite.index.Conflicts(), component.ErrDuplicateKey)
+	owner, _ := suite.index.Lookup("player")
+	assert.Equal(suite.T(), first, owner)
+
+	// The claimant takes over once the owner releases the key
+	suite.nameLinker.Unlink(first)
+	sandbox.Update(suite.sandbox)
+	owner, _ = suite.index.Lookup("player")
+	assert.Equal(suite.T(), second, owner)
+	assert.NoError(suite.T(), suite.index.Conflicts())
+}
+
+func (suite *IndexTestSuite) TestIndex_ExistingConflicts() {
+	entityIds := make([]entity.Id, 6)
+	for index := range entityIds {
+		entityIds[index] = sandbox.LinkEntity(suite.sandbox)
+		suite.nameLinker.Link(entityIds[index]).value = []string{"a", "b", "c"}[index%3]
+	}
+	sandbox.Update(suite.sandbox)
+
+	// Conflicts between entities linked before the index was created reach the callback set after it
+	index := sandbox.Index(suite.sandbox, func(component *name) string {
+		return component.value
+	})
+	conflicts := 0
+	index.OnConflict(func(string, entity.Id, entity.Id) {
+		conflicts++
+	})
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), 3, conflicts)
+
+	// Conflicts are ordered by owner
+	err := index.Conflicts()
+	assert.ErrorIs(suite.T(), err, component.ErrDuplicateKey)
+	assert.Equal(suite.T(), "component: duplicate key a: owned by entity 0, claimed by entities [3]\n"+
+		"component: duplicate key b: owned by entity 1, claimed by entities [4]\n"+
+		"component: duplicate key c: owned by entity 2, claimed by entities [5]", err.Error())
+}
+
+func (suite *IndexTestSuite) TestIndex_Release() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.nameLinker.Link(entityId).value = "kept"
+	sandbox.Update(suite.sandbox)
+	suite.index.Release()
+	suite.index.Release()
+
+	// Released indexes are no longer updated
+	suite.nameLinker.Get(entityId).value = "renamed"
+	suite.nameLinker.MarkChanged(entityId)
+	sandbox.Update(suite.sandbox)
+	owner, ok := suite.index.Lookup("kept")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), entityId, owner)
+	_, ok = suite.index.Lookup("renamed")
+	assert.False(suite.T(), ok)
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/inspect"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestInspectSuite(t *testing.T) {
+	suite.Run(t, &InspectTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &InspectTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &InspectTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type InspectTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	velocityLinker component.Linker[velocity]
+	renderedLinker component.TagLinker
+}
+
+func (suite *InspectTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.velocityLinker = sandbox.ComponentLinker[velocity](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+}
+
+func (suite *InspectTestSuite) TestInspect_Components() {
+	for index := range 3 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId)
+		if index == 0 {
+			suite.renderedLinker.Link(entityId)
+		}
+	}
+	sandbox.Update(suite.sandbox)
+
+	assert.Equal(suite.T(), []inspect.Component{
+		{Id: 0, Name: testsPath + "position", Kind: inspect.ComponentKind, Mode: suite.mode, Linked: 3},
+		{Id: 1, Name: testsPath + "velocity", Kind: inspect.ComponentKind, Mode: suite.mode, Linked: 0},
+		{Id: 2, Name: renderedComponent, Kind: inspect.TagKind, Linked: 1},
+	}, sandbox.Components(suite.sandbox))
+	assert.Equal(suite.T(), "tag", inspect.TagKind.String())
+}
+
+func (suite *InspectTestSuite) TestInspect_Filters() {
+	sandbox.Filter(suite.sandbox, filter.Match[position](), filter.Exclude[velocity]())
+	sandbox.Filter(suite.sandbox, filter.UnionTags(renderedComponent))
+	firstId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(firstId)
+	secondId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(secondId)
+	suite.velocityLinker.Link(secondId)
+	suite.renderedLinker.Link(secondId)
+	sandbox.Update(suite.sandbox)
+
+	assert.Equal(suite.T(), []inspect.Filter{
+		{
+			Name:     "Match(" + testsPath + "position) Exclude(" + testsPath + "velocity)",
+			Match:    []string{testsPath + "position"},
+			Exclude:  []string{testsPath + "velocity"},
+			Union:    []string{},
+			Entities: 1,
+			Views:    1,
+		},
+		{
+			Name:     "Union(" + renderedComponent + ")",
+			Match:    []string{},
+			Exclude:  []string{},
+			Union:    []string{renderedComponent},
+			Entities: 1,
+			Views:    1,
+		},
+	}, sandbox.Filters(suite.sandbox))
+}
+
+func (suite *InspectTestSuite) TestInspect_EntityComponents() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.velocityLinker.Link(entityId)
+	suite.renderedLinker.Link(entityId)
+	emptyId := sandbox.LinkEntity(suite.sandbox)
+	sandbox.Update(suite.sandbox)
+
+	descriptors := sandbox.EntityComponents(suite.sandbox, entityId)
+	if assert.Len(suite.T(), descriptors, 2) {
+		assert.Equal(suite.T(), testsPath+"velocity", descriptors[0].Name)
+		assert.Equal(suite.T(), renderedComponent, descriptors[1].Name)
+	}
+	assert.Empty(suite.T(), sandbox.EntityComponents(suite.sandbox, emptyId))
+	assert.Empty(suite.T(), sandbox.EntityComponents(suite.sandbox, emptyId+1))
+}
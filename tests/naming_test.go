@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	alpha "github.com/andrei-cosmin/sandecs/tests/fixtures/alpha"
+	beta "github.com/andrei-cosmin/sandecs/tests/fixtures/beta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestNamingSuite(t *testing.T) {
+	suite.Run(t, &NamingTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &NamingTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &NamingTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type NamingTestSuite struct {
+	sandboxSuite
+	mode     options.Mode
+	poolSize uint
+}
+
+func (suite *NamingTestSuite) SetupTest() {
+	suite.sandbox = suite.newSandbox()
+}
+
+func (suite *NamingTestSuite) newSandbox() *sandbox.Sandbox {
+	return sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+}
+
+func (suite *NamingTestSuite) componentNames(s *sandbox.Sandbox) []string {
+	var names []string
+	for _, descriptor := range sandbox.Components(s) {
+		names = append(names, descriptor.Name)
+	}
+	return names
+}
+
+func (suite *NamingTestSuite) TestNaming_SameShortTypeName() {
+	alphaLinker := sandbox.ComponentLinker[alpha.Position](suite.sandbox)
+	betaLinker := sandbox.ComponentLinker[beta.Position](suite.sandbox)
+	assert.NotEqual(suite.T(), alphaLinker.ComponentId(), betaLinker.ComponentId())
+	assert.Equal(suite.T(), []string{
+		"github.com/andrei-cosmin/sandecs/tests/fixtures/alpha.Position",
+		"github.com/andrei-cosmin/sandecs/tests/fixtures/beta.Position",
+	}, suite.componentNames(suite.sandbox))
+
+	// Default names don't depend on the registration order
+	other := suite.newSandbox()
+	sandbox.ComponentLinker[beta.Position](other)
+	sandbox.ComponentLinker[alpha.Position](other)
+	assert.ElementsMatch(suite.T(), suite.componentNames(suite.sandbox), suite.componentNames(other))
+
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	alphaLinker.Link(entityId).X = 1
+	betaLinker.Link(entityId).Z = 2
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), alpha.Position{X: 1}, *alphaLinker.Get(entityId))
+	assert.Equal(suite.T(), beta.Position{Z: 2}, *betaLinker.Get(entityId))
+	assert.Equal(suite.T(), []uint{entityId}, sandbox.QueryOnce(suite.sandbox, filter.Match[beta.Position]()))
+}
+
+func (suite *NamingTestSuite) TestNaming_TagsAndComponentsDontAlias() {
+	positionLinker := sandbox.ComponentLinker[position](suite.sandbox)
+	tagLinker := sandbox.TagLinker(suite.sandbox, testsPath+"position")
+	assert.NotEqual(suite.T(), positionLinker.ComponentId(), tagLinker.ComponentId())
+
+	tagged, positioned := sandbox.LinkEntity(suite.sandbox), sandbox.LinkEntity(suite.sandbox)
+	tagLinker.Link(tagged)
+	positionLinker.Link(positioned).X = 3
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []uint{positioned}, sandbox.QueryOnce(suite.sandbox, filter.Match[position]()))
+	assert.Equal(suite.T(), []uint{tagged}, sandbox.QueryOnce(suite.sandbox, filter.MatchTags(testsPath+"position")))
+
+	// Snapshots keep both apart
+	var buffer bytes.Buffer
+	suite.Require().NoError(sandbox.Save(suite.sandbox, &buffer, snapshot.Binary))
+	loaded := suite.newSandbox()
+	loadedPositions := sandbox.ComponentLinker[position](loaded)
+	suite.Require().NoError(sandbox.Load(loaded, &buffer))
+	assert.True(suite.T(), sandbox.TagLinker(loaded, testsPath+"position").Has(tagged))
+	assert.False(suite.T(), loadedPositions.Has(tagged))
+	assert.Equal(suite.T(), 3.0, loadedPositions.Get(positioned).X)
+}
+
+func (suite *NamingTestSuite) TestNaming_RegisterName() {
+	suite.Require().NoError(sandbox.RegisterName[position](suite.sandbox, "physics/position"))
+	assert.NoError(suite.T(), sandbox.RegisterName[position](suite.sandbox, "physics/position"))
+	assert.ErrorIs(suite.T(), sandbox.RegisterName[position](suite.sandbox, "other"), component.ErrNameConflict)
+	assert.ErrorIs(suite.T(), sandbox.RegisterName[velocity](suite.sandbox, "physics/position"), component.ErrNameConflict)
+
+	// Registered names are used for introspection, name-based access and snapshots
+	positionLinker := sandbox.ComponentLinker[position](suite.sandbox)
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	positionLinker.Link(entityId).X = 4
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []string{"physics/position"}, suite.componentNames(suite.sandbox))
+	instance, ok := sandbox.GetByName(suite.sandbox, entityId, "physics/position")
+	assert.True(suite.T(), ok)
+	assert.Same(suite.T(), positionLinker.Get(entityId), instance)
+	_, ok = sandbox.GetByName(suite.sandbox, entityId, testsPath+"position")
+	assert.False(suite.T(), ok)
+
+	var buffer bytes.Buffer
+	suite.Require().NoError(sandbox.Save(suite.sandbox, &buffer, snapshot.JSON))
+	loaded := suite.newSandbox()
+	suite.Require().NoError(sandbox.RegisterName[position](loaded, "physics/position"))
+	suite.Require().NoError(sandbox.Load(loaded, &buffer))
+	assert.Equal(suite.T(), 4.0, sandbox.ComponentLinker[position](loaded).Get(entityId).X)
+
+	// The type name is free for other components once the type is registered under another name
+	assert.NoError(suite.T(), sandbox.RegisterName[velocity](suite.sandbox, testsPath+"position"))
+}
+
+func (suite *NamingTestSuite) TestNaming_SameShortTagName() {
+	alphaLinker := sandbox.TypedTagLinker[alpha.Marker](suite.sandbox)
+	betaLinker := sandbox.TypedTagLinker[beta.Marker](suite.sandbox)
+	assert.NotEqual(suite.T(), alphaLinker.ComponentId(), betaLinker.ComponentId())
+	assert.Same(suite.T(), alphaLinker, sandbox.TagLinker(suite.sandbox, "github.com/andrei-cosmin/sandecs/tests/fixtures/alpha.Marker"))
+	assert.Same(suite.T(), betaLinker, sandbox.TagLinker(suite.sandbox, "github.com/andrei-cosmin/sandecs/tests/fixtures/beta.Marker"))
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	enemyTag  = "ENEMY"
+	rangedTag = "RANGED"
+)
+
+func TestPrefabSuite(t *testing.T) {
+	suite.Run(t, &PrefabTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &PrefabTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &PrefabTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type PrefabTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	goblin         *sandbox.Prefab
+	archer         *sandbox.Prefab
+	positionLinker component.Linker[position]
+	healthLinker   component.Linker[health]
+	armorLinker    component.Linker[armor]
+	enemyLinker    component.TagLinker
+	rangedLinker   component.TagLinker
+}
+
+func (suite *PrefabTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.healthLinker = sandbox.ComponentLinker[health](suite.sandbox)
+	suite.armorLinker = sandbox.ComponentLinker[armor](suite.sandbox)
+	suite.enemyLinker = sandbox.TagLinker(suite.sandbox, enemyTag)
+	suite.rangedLinker = sandbox.TagLinker(suite.sandbox, rangedTag)
+
+	suite.goblin = sandbox.NewPrefab().Tag(enemyTag)
+	sandbox.WithComponent(suite.goblin, position{X: 1, Y: 2})
+	sandbox.WithComponent(suite.goblin, health{value: 50})
+
+	suite.archer = suite.goblin.Extend().Tag(rangedTag, enemyTag)
+	sandbox.WithComponent(suite.archer, health{value: 30})
+	sandbox.WithComponent(suite.archer, armor{value: 2})
+}
+
+func (suite *PrefabTestSuite) TestPrefab_Spawn() {
+	goblinId := suite.goblin.Spawn(suite.sandbox)
+	archerId := suite.archer.Spawn(suite.sandbox)
+
+	assert.Equal(suite.T(), position{X: 1, Y: 2}, *suite.positionLinker.Get(goblinId), componentValueMsg, positionComponent, goblinId)
+	assert.Equal(suite.T(), health{value: 50}, *suite.healthLinker.Get(goblinId), componentValueMsg, healthComponent, goblinId)
+	suite.assertDeletedComponent(suite.armorLinker, goblinId, componentNotUnlinkedMsg, armorComponent, goblinId)
+	suite.assertComponent(suite.enemyLinker, goblinId, componentNotLinkedMsg, enemyTag, goblinId)
+	suite.assertDeletedComponent(suite.rangedLinker, goblinId, componentNotUnlinkedMsg, rangedTag, goblinId)
+
+	assert.Equal(suite.T(), position{X: 1, Y: 2}, *suite.positionLinker.Get(archerId), componentValueMsg, positionComponent, archerId)
+	assert.Equal(suite.T(), health{value: 30}, *suite.healthLinker.Get(archerId), componentValueMsg, healthComponent, archerId)
+	assert.Equal(suite.T(), armor{value: 2}, *suite.armorLinker.Get(archerId), componentValueMsg, armorComponent, archerId)
+	suite.assertComponent(suite.enemyLinker, archerId, componentNotLinkedMsg, enemyTag, archerId)
+	suite.assertComponent(suite.rangedLinker, archerId, componentNotLinkedMsg, rangedTag, archerId)
+
+	// Spawned values are copies of the prefab defaults
+	suite.positionLinker.Get(goblinId).X = 10
+	assert.Equal(suite.T(), float64(1), suite.positionLinker.Get(suite.goblin.Spawn(suite.sandbox)).X)
+}
+
+func (suite *PrefabTestSuite) TestPrefab_SpawnN() {
+	linkCount := 0
+	suite.healthLinker.SetLinkHook(func(*health) {
+		linkCount++
+	})
+	view := sandbox.Filter(suite.sandbox, filter.Match2[position, health](), filter.MatchTags(rangedTag))
+
+	for range numEntities {
+		sandbox.LinkEntity(suite.sandbox)
+	}
+	removedEntities := getRandomIds(numEntities, numRemoves)
+	for _, entityId := range removedEntities {
+		sandbox.UnlinkEntity(suite.sandbox, entityId)
+	}
+	sandbox.Update(suite.sandbox)
+
+	// Spawning fills the recycled ids first, then grows
+	archerIds := suite.archer.SpawnN(suite.sandbox, numRemoves*2)
+	assert.Len(suite.T(), archerIds, numRemoves*2)
+	for index := 1; index < len(archerIds); index++ {
+		assert.Less(suite.T(), archerIds[index-1], archerIds[index], entityRecycleOrderMsg, archerIds[index])
+	}
+	assert.Equal(suite.T(), entity.Id(numEntities), archerIds[numRemoves], entityRecycleOrderMsg, archerIds[numRemoves])
+
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), archerIds, view.EntityIds(), filterIncorrectNumEntitiesMsg)
+	assert.Equal(suite.T(), numRemoves*2, linkCount)
+	for _, entityId := range archerIds {
+		suite.assertEntity(entityId, entityNotLinkedMsg, entityId)
+		assert.Equal(suite.T(), health{value: 30}, *suite.healthLinker.Get(entityId), componentValueMsg, healthComponent, entityId)
+		assert.Equal(suite.T(), armor{value: 2}, *suite.armorLinker.Get(entityId), componentValueMsg, armorComponent, entityId)
+	}
+}
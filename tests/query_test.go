@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestQuerySuite(t *testing.T) {
+	suite.Run(t, &QueryTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &QueryTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &QueryTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type QueryTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	healthLinker   component.Linker[health]
+	armorLinker    component.Linker[armor]
+}
+
+func (suite *QueryTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.healthLinker = sandbox.ComponentLinker[health](suite.sandbox)
+	suite.armorLinker = sandbox.ComponentLinker[armor](suite.sandbox)
+}
+
+func (suite *QueryTestSuite) TestQuery_MatchesRegisteredFilter() {
+	for index := range 100 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId)
+		if index%2 == 0 {
+			suite.healthLinker.Link(entityId)
+		}
+		if index%3 == 0 {
+			suite.armorLinker.Link(entityId)
+		}
+	}
+	sandbox.Update(suite.sandbox)
+
+	filters := []filter.Filter{filter.Match2[position, health](), filter.Exclude[armor]()}
+	view := sandbox.Filter(suite.sandbox, filters...)
+	defer view.Release()
+	assert.Equal(suite.T(), view.EntityIds(), sandbox.QueryOnce(suite.sandbox, filters...))
+}
+
+func (suite *QueryTestSuite) TestQuery_IncludesPendingLinks() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.healthLinker.Link(entityId)
+
+	// No filter is registered, and the links weren't processed by Update yet
+	assert.Equal(suite.T(), []entity.Id{entityId}, sandbox.QueryOnce(suite.sandbox, filter.Match[health]()))
+	assert.Equal(suite.T(), []entity.Id{entityId}, sandbox.QueryOnce(suite.sandbox, filter.Exclude[armor]()))
+	assert.Empty(suite.T(), sandbox.QueryOnce(suite.sandbox, filter.Match2[health, armor]()))
+	assert.Empty(suite.T(), sandbox.Filters(suite.sandbox))
+
+	sandbox.Update(suite.sandbox)
+	assert.Zero(suite.T(), sandbox.Stats(suite.sandbox).FiltersRecomputed)
+}
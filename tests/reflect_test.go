@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestReflectSuite(t *testing.T) {
+	suite.Run(t, &ReflectTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &ReflectTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &ReflectTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type ReflectTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	statsLinker    component.Linker[stats]
+}
+
+func (suite *ReflectTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.statsLinker = sandbox.ComponentLinker[stats](suite.sandbox)
+	sandbox.TagLinker(suite.sandbox, renderedComponent)
+}
+
+func (suite *ReflectTestSuite) TestReflect_GetByName() {
+	// Unlink some components first, so pooled and compact tables reuse or move storage
+	for index := range 10 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId).X = float64(index)
+	}
+	sandbox.Update(suite.sandbox)
+	for index := 0; index < 10; index += 3 {
+		suite.positionLinker.Unlink(entity.Id(index))
+	}
+	sandbox.Update(suite.sandbox)
+
+	for index := range 10 {
+		instance, ok := sandbox.GetByName(suite.sandbox, entity.Id(index), testsPath+"position")
+		if index%3 == 0 {
+			assert.False(suite.T(), ok, index)
+			continue
+		}
+		if assert.True(suite.T(), ok, index) {
+			assert.Same(suite.T(), suite.positionLinker.Get(entity.Id(index)), instance)
+			assert.Equal(suite.T(), float64(index), instance.(*position).X)
+		}
+	}
+
+	_, ok := sandbox.GetByName(suite.sandbox, 1, "tests.missing")
+	assert.False(suite.T(), ok)
+	_, ok = sandbox.GetByName(suite.sandbox, 1, renderedComponent)
+	assert.False(suite.T(), ok)
+}
+
+func (suite *ReflectTestSuite) TestReflect_SetField() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.statsLinker.Link(entityId)
+	sandbox.Update(suite.sandbox)
+
+	var changed []entity.Id
+	sandbox.Observe[stats](suite.sandbox, func(entityId entity.Id, _ *stats) {
+		changed = append(changed, entityId)
+	}, nil)
+	sandbox.Update(suite.sandbox)
+	changed = changed[:0]
+
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Level", 3))
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Speed.X", 1.5))
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Speed.Y", 2))
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Tags", []string{"fast"}))
+	assert.Equal(suite.T(), stats{Level: 3, Speed: velocity{X: 1.5, Y: 2}, Tags: []string{"fast"}}, *suite.statsLinker.Get(entityId))
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Tags", nil))
+	assert.Nil(suite.T(), suite.statsLinker.Get(entityId).Tags)
+
+	// Components written by name are marked changed
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{entityId}, changed)
+}
+
+func (suite *ReflectTestSuite) TestReflect_SetFieldErrors() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.statsLinker.Link(entityId)
+	sandbox.Update(suite.sandbox)
+
+	assert.ErrorIs(suite.T(), sandbox.SetField(suite.sandbox, entityId, "tests.missing", "X", 1), component.ErrUnknownComponent)
+	assert.ErrorIs(suite.T(), sandbox.SetField(suite.sandbox, entityId, renderedComponent, "X", 1), component.ErrUnknownComponent)
+	assert.ErrorIs(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"position", "X", 1), component.ErrNotLinked)
+	assert.ErrorIs(suite.T(), sandbox.SetField(suite.sandbox, 100, testsPath+"stats", "Level", 1), component.ErrEntityNotLinked)
+
+	for _, path := range []string{"Missing", "secret", "Speed.Z", "Level.X"} {
+		assert.ErrorIs(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", path, 1), component.ErrUnknownField, path)
+	}
+	for _, value := range []any{300, -1, 1.5, "3", nil} {
+		assert.ErrorIs(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Level", value), component.ErrFieldKind, value)
+	}
+	assert.Zero(suite.T(), suite.statsLinker.Get(entityId).Level)
+
+	// Conversions are range checked against the target type, not round-tripped through the source type
+	for _, value := range []any{uint64(math.MaxUint64), uint64(math.MaxInt64) + 1, math.Ldexp(1, 63), math.NaN(), math.Inf(1)} {
+		assert.ErrorIs(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Score", value), component.ErrFieldKind, value)
+	}
+	assert.Zero(suite.T(), suite.statsLinker.Get(entityId).Score)
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Score", uint64(math.MaxInt64)))
+	assert.Equal(suite.T(), int64(math.MaxInt64), suite.statsLinker.Get(entityId).Score)
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Score", -math.Ldexp(1, 63)))
+	assert.Equal(suite.T(), int64(math.MinInt64), suite.statsLinker.Get(entityId).Score)
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, testsPath+"stats", "Level", 255.0))
+	assert.Equal(suite.T(), uint8(255), suite.statsLinker.Get(entityId).Level)
+}
+
+func (suite *ReflectTestSuite) TestReflect_DynamicComponents() {
+	healthLinker, err := sandbox.DynamicLinker(suite.sandbox, "health", component.Field{Name: "current", Kind: component.IntField})
+	suite.Require().NoError(err)
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	healthLinker.Link(entityId)
+
+	assert.NoError(suite.T(), sandbox.SetField(suite.sandbox, entityId, "health", "current", 5))
+	instance, ok := sandbox.GetByName(suite.sandbox, entityId, "health")
+	if assert.True(suite.T(), ok) {
+		assert.Equal(suite.T(), int64(5), instance.(*component.Record).Int("current"))
+	}
+	assert.ErrorIs(suite.T(), sandbox.SetField(suite.sandbox, entityId, "health", "max", 5), component.ErrUnknownField)
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestReleaseSuite(t *testing.T) {
+	suite.Run(t, &ReleaseTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &ReleaseTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &ReleaseTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type ReleaseTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+}
+
+func (suite *ReleaseTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize, options.TrackViews)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+}
+
+func (suite *ReleaseTestSuite) TestRelease_SharedViews() {
+	first := sandbox.Filter(suite.sandbox, filter.Match[position]())
+	second := sandbox.Filter(suite.sandbox, filter.Match[position]())
+	if assert.Len(suite.T(), sandbox.Filters(suite.sandbox), 1) {
+		assert.Equal(suite.T(), uint(2), sandbox.Filters(suite.sandbox)[0].Views)
+	}
+
+	// The filter is still updated while a view references it
+	first.Release()
+	first.Release()
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(entityId)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []uint{entityId}, second.EntityIds())
+	if assert.Len(suite.T(), sandbox.Filters(suite.sandbox), 1) {
+		assert.Equal(suite.T(), uint(1), sandbox.Filters(suite.sandbox)[0].Views)
+	}
+
+	// Once all views are released, the filter is unregistered
+	second.Release()
+	assert.Empty(suite.T(), sandbox.Filters(suite.sandbox))
+	assert.Empty(suite.T(), sandbox.Views(suite.sandbox))
+	suite.positionLinker.Link(sandbox.LinkEntity(suite.sandbox))
+	sandbox.Update(suite.sandbox)
+	assert.Zero(suite.T(), sandbox.Stats(suite.sandbox).FiltersRecomputed)
+
+	// Creating the filter again registers a new, up to date cache
+	third := sandbox.Filter(suite.sandbox, filter.Match[position]())
+	assert.Len(suite.T(), third.EntityIds(), 2)
+}
+
+func (suite *ReleaseTestSuite) TestRelease_TrackViews() {
+	view := sandbox.Filter(suite.sandbox, filter.Match[position]())
+	views := sandbox.Views(suite.sandbox)
+	if assert.Len(suite.T(), views, 1) {
+		assert.Equal(suite.T(), "Match("+testsPath+"position)", views[0].Filter)
+		assert.True(suite.T(), strings.Contains(views[0].Site, "release_test.go:"), views[0].Site)
+		assert.False(suite.T(), views[0].Leaked)
+	}
+	view.Release()
+
+	// Views of typed queries record the caller too, not the query constructor
+	_, file, line, _ := runtime.Caller(0)
+	query := sandbox.NewQuery1[position](suite.sandbox)
+	views = sandbox.Views(suite.sandbox)
+	if assert.Len(suite.T(), views, 1) {
+		assert.Equal(suite.T(), file+":"+strconv.Itoa(line+1), views[0].Site)
+	}
+	query.Release()
+
+	// Views garbage collected without being released are reported as leaked
+	suite.leakView()
+	assert.Eventually(suite.T(), func() bool {
+		runtime.GC()
+		views := sandbox.Views(suite.sandbox)
+		return len(views) == 1 && views[0].Leaked
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *ReleaseTestSuite) TestRelease_UntrackedViews() {
+	untracked := sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	first := sandbox.Filter(untracked, filter.Match[position]())
+	sandbox.Filter(untracked, filter.Match[position]())
+	sandbox.Filter(untracked, filter.Exclude[position]())
+
+	// Without TrackViews, views aren't recorded, but filters still count their references
+	assert.Empty(suite.T(), sandbox.Views(untracked))
+	if assert.Len(suite.T(), sandbox.Filters(untracked), 2) {
+		assert.Equal(suite.T(), uint(2), sandbox.Filters(untracked)[0].Views)
+	}
+	first.Release()
+	if assert.Len(suite.T(), sandbox.Filters(untracked), 2) {
+		assert.Equal(suite.T(), uint(1), sandbox.Filters(untracked)[0].Views)
+	}
+}
+
+func (suite *ReleaseTestSuite) leakView() {
+	sandbox.Filter(suite.sandbox, filter.Exclude[position]())
+}
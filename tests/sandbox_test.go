@@ -444,6 +444,33 @@ func (suite *SandboxTestSuite) TestSandbox_SimpleFilter() {
 	}
 }
 
+func (suite *SandboxTestSuite) TestSandbox_LateFilter() {
+	for index := range numEntities {
+		sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entity.Id(index))
+	}
+	sandbox.Update(suite.sandbox)
+
+	// Filters registered after the first update are computed right away
+	positionFilter := sandbox.Filter(suite.sandbox, filter.Match[position]())
+	assert.Len(suite.T(), positionFilter.EntityIds(), numEntities, filterIncorrectNumEntitiesMsg)
+}
+
+func (suite *SandboxTestSuite) TestSandbox_ExcludeFilterBareEntities() {
+	excludeFilter := sandbox.Filter(suite.sandbox, filter.Exclude[velocity]())
+	sandbox.Update(suite.sandbox)
+
+	// Entities without components are picked up by the next update, whether linked one at a time or in a batch
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{entityId}, excludeFilter.EntityIds(), filterIncorrectNumEntitiesMsg)
+
+	entityIds := sandbox.NewPrefab().SpawnN(suite.sandbox, numEntities)
+	sandbox.Update(suite.sandbox)
+	assert.Len(suite.T(), excludeFilter.EntityIds(), numEntities+1, filterIncorrectNumEntitiesMsg)
+	assert.True(suite.T(), excludeFilter.EntityMask().Test(entityIds[numEntities-1]))
+}
+
 func (suite *SandboxTestSuite) TestSandbox_HeavyFilter() {
 	armorTagHandler := sandbox.TagLinker(suite.sandbox, armorComponent)
 	filter1 := sandbox.Filter(suite.sandbox, filter.Match2[position, velocity](), filter.ExcludeTags(armorComponent))
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/andrei-cosmin/sandata/bit"
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/bits-and-blooms/bitset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSetSuite(t *testing.T) {
+	suite.Run(t, &SetTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &SetTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &SetTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type SetTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	healthLinker   component.Linker[health]
+	view           filter.View
+	selection      *bit.BitMask
+}
+
+func (suite *SetTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.healthLinker = sandbox.ComponentLinker[health](suite.sandbox)
+	suite.view = sandbox.Filter(suite.sandbox, filter.Match[position]())
+	suite.selection = bit.NewMask(bitset.New(0))
+
+	for index := range 100 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		if index%2 == 0 {
+			suite.positionLinker.Link(entityId)
+		}
+		if index%3 == 0 {
+			suite.healthLinker.Link(entityId)
+		}
+		if index%5 == 0 {
+			suite.selection.Bits().Set(entityId)
+		}
+	}
+	sandbox.Update(suite.sandbox)
+}
+
+func (suite *SetTestSuite) TestSet_View() {
+	assert.Equal(suite.T(), uint(50), suite.view.Count())
+	assert.True(suite.T(), suite.view.Any())
+	assert.True(suite.T(), suite.view.Contains(2))
+	assert.False(suite.T(), suite.view.Contains(3))
+	first, ok := suite.view.First()
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), entity.Id(0), first)
+}
+
+func (suite *SetTestSuite) TestSet_Algebra() {
+	// Entities with position, selected, without health: multiples of 10, not multiples of 3
+	set := suite.view.And(suite.selection).AndNot(suite.healthLinker.EntityMask())
+	defer set.Release()
+
+	expected := []entity.Id{10, 20, 40, 50, 70, 80}
+	assert.Equal(suite.T(), expected, slices.Collect(set.All()))
+	assert.Equal(suite.T(), uint(len(expected)), set.Count())
+	assert.True(suite.T(), set.Contains(20))
+	assert.False(suite.T(), set.Contains(30))
+	first, ok := set.First()
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), entity.Id(10), first)
+
+	// The view is left unchanged
+	assert.Equal(suite.T(), uint(50), suite.view.Count())
+	empty := suite.view.AndNot(suite.view.EntityMask())
+	assert.False(suite.T(), empty.Any())
+	empty.Release()
+}
+
+func (suite *SetTestSuite) TestSet_PooledBuffers() {
+	mask := suite.healthLinker.EntityMask()
+	allocations := testing.AllocsPerRun(100, func() {
+		set := suite.view.And(mask).AndNot(suite.selection)
+		entities := set.EntityMask()
+		for entityId, hasNext := entities.NextSet(0); hasNext; entityId, hasNext = entities.NextSet(entityId + 1) {
+			set.Contains(entityId)
+		}
+		set.Release()
+	})
+	assert.Zero(suite.T(), allocations)
+}
@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSnapshotSuite(t *testing.T) {
+	suite.Run(t, &SnapshotTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &SnapshotTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &SnapshotTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type SnapshotTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	velocityLinker component.Linker[velocity]
+	renderedLinker component.TagLinker
+	view           entity.View
+}
+
+func (suite *SnapshotTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.velocityLinker = sandbox.ComponentLinker[velocity](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+	suite.view = sandbox.Filter(suite.sandbox, filter.Match2[position, velocity](), filter.MatchTags(renderedComponent))
+
+	for index := range numEntities {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId).X = float64(index)
+		if index%2 == 0 {
+			suite.velocityLinker.Link(entityId).Y = float64(index * 2)
+		}
+		if index%3 == 0 {
+			suite.renderedLinker.Link(entityId)
+		}
+	}
+	sandbox.Update(suite.sandbox)
+
+	for index := 0; index < numEntities; index += 5 {
+		sandbox.UnlinkEntity(suite.sandbox, entity.Id(index))
+	}
+	sandbox.Update(suite.sandbox)
+}
+
+func (suite *SnapshotTestSuite) save(format snapshot.Format) *bytes.Buffer {
+	var buffer bytes.Buffer
+	assert.NoError(suite.T(), sandbox.Save(suite.sandbox, &buffer, format))
+	return &buffer
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_JSONRoundTrip() {
+	suite.assertRoundTrip(snapshot.JSON)
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_BinaryRoundTrip() {
+	suite.assertRoundTrip(snapshot.Binary)
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_BinarySmallerThanJSON() {
+	assert.Less(suite.T(), suite.save(snapshot.Binary).Len(), suite.save(snapshot.JSON).Len())
+}
+
+func (suite *SnapshotTestSuite) assertRoundTrip(format snapshot.Format) {
+	buffer := suite.save(format)
+
+	// Register the linkers in a different order, so component ids differ from the saved sandbox
+	loaded := sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	velocityLinker := sandbox.ComponentLinker[velocity](loaded)
+	positionLinker := sandbox.ComponentLinker[position](loaded)
+	view := sandbox.Filter(loaded, filter.Match2[position, velocity](), filter.MatchTags(renderedComponent))
+	assert.NotEqual(suite.T(), suite.positionLinker.ComponentId(), positionLinker.ComponentId())
+
+	assert.NoError(suite.T(), sandbox.Load(loaded, buffer))
+	renderedLinker := sandbox.TagLinker(loaded, renderedComponent)
+
+	for index := range numEntities {
+		entityId := entity.Id(index)
+		assert.Equal(suite.T(), sandbox.IsEntityLinked(suite.sandbox, entityId), sandbox.IsEntityLinked(loaded, entityId), entityNotLinkedMsg, index)
+		assert.Equal(suite.T(), suite.positionLinker.Has(entityId), positionLinker.Has(entityId), componentNotLinkedMsg, positionComponent, index)
+		assert.Equal(suite.T(), suite.velocityLinker.Has(entityId), velocityLinker.Has(entityId), componentNotLinkedMsg, velocityComponent, index)
+		assert.Equal(suite.T(), suite.renderedLinker.Has(entityId), renderedLinker.Has(entityId), componentNotLinkedMsg, renderedComponent, index)
+		if positionLinker.Has(entityId) {
+			assert.Equal(suite.T(), *suite.positionLinker.Get(entityId), *positionLinker.Get(entityId), componentValueMsg, positionComponent, index)
+		}
+		if velocityLinker.Has(entityId) {
+			assert.Equal(suite.T(), *suite.velocityLinker.Get(entityId), *velocityLinker.Get(entityId), componentValueMsg, velocityComponent, index)
+		}
+	}
+
+	assert.NotEmpty(suite.T(), view.EntityIds(), filterIncorrectNumEntitiesMsg)
+	assert.Equal(suite.T(), suite.view.EntityIds(), view.EntityIds(), filterIncorrectNumEntitiesMsg)
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_LoadReplacesContents() {
+	buffer := suite.save(snapshot.JSON)
+
+	for index := range numEntities / 5 {
+		sandbox.UnlinkEntity(suite.sandbox, entity.Id(index))
+	}
+	sandbox.Update(suite.sandbox)
+	extraId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(extraId).X = -1
+	suite.renderedLinker.Link(extraId)
+	sandbox.Update(suite.sandbox)
+
+	assert.NoError(suite.T(), sandbox.Load(suite.sandbox, buffer))
+	for index := range numEntities {
+		assert.Equal(suite.T(), index%5 != 0, sandbox.IsEntityLinked(suite.sandbox, entity.Id(index)), entityNotLinkedMsg, index)
+	}
+	suite.assertDeletedComponent(suite.positionLinker, extraId, componentNotUnlinkedMsg, positionComponent, extraId)
+	suite.assertDeletedComponent(suite.renderedLinker, extraId, componentNotUnlinkedMsg, renderedComponent, extraId)
+	assert.Equal(suite.T(), float64(numEntities-1), suite.positionLinker.Get(numEntities-1).X, componentValueMsg, positionComponent, numEntities-1)
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_LoadRunsUpdateHooks() {
+	index := sandbox.Index[position, float64](suite.sandbox, func(p *position) float64 {
+		return p.X
+	})
+	frames := 0
+	sandbox.OnUpdate(suite.sandbox, func() {
+		frames++
+	})
+	buffer := suite.save(snapshot.Binary)
+
+	suite.positionLinker.Get(1).X = 1000
+	suite.positionLinker.MarkChanged(1)
+	sandbox.Update(suite.sandbox)
+	owner, ok := index.Lookup(1000)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), uint(1), owner)
+
+	// Derived structures see the restored values once the load completes
+	frames = 0
+	assert.NoError(suite.T(), sandbox.Load(suite.sandbox, buffer))
+	assert.Equal(suite.T(), 1, frames)
+	_, ok = index.Lookup(1000)
+	assert.False(suite.T(), ok)
+	owner, ok = index.Lookup(1)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), uint(1), owner)
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_UnknownComponent() {
+	buffer := suite.save(snapshot.JSON)
+
+	loaded := sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	sandbox.ComponentLinker[position](loaded)
+	assert.ErrorIs(suite.T(), sandbox.Load(loaded, buffer), snapshot.ErrUnknownComponent)
+	assert.False(suite.T(), sandbox.IsEntityLinked(loaded, 1))
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_InvalidInput() {
+	assert.ErrorIs(suite.T(), sandbox.Load(suite.sandbox, bytes.NewBufferString("garbage")), snapshot.ErrUnknownFormat)
+	assert.ErrorIs(suite.T(), sandbox.Load(suite.sandbox, bytes.NewBufferString(`{"version":1,"entities":[1],"tags":[{"name":"x","entities":[2]}]}`)), snapshot.ErrInvalidSnapshot)
+
+	// Entity ids size the decoded bitsets, so huge ids are rejected instead of allocated
+	assert.ErrorIs(suite.T(), sandbox.Load(suite.sandbox, bytes.NewBufferString(`{"version":1,"entities":[18446744073709551615]}`)), snapshot.ErrInvalidSnapshot)
+	assert.ErrorIs(suite.T(), sandbox.Load(suite.sandbox, bytes.NewBufferString(`{"version":1,"entities":[1],"tags":[{"name":"x","entities":[4294967296]}]}`)), snapshot.ErrInvalidSnapshot)
+	assert.True(suite.T(), sandbox.IsEntityLinked(suite.sandbox, 1))
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_BinaryMigration() {
+	encoded := saveOlderVelocity(suite.T(), suite.mode, suite.poolSize)
+	assert.ErrorIs(suite.T(), sandbox.Load(suite.sandbox, bytes.NewReader(encoded)), snapshot.ErrSchemaMismatch)
+
+	sandbox.RegisterMigration(suite.sandbox, func(old *olderVelocity, current *velocity) {
+		current.X = old.Speed
+		current.Y = -old.Speed
+	})
+	assert.NoError(suite.T(), sandbox.Load(suite.sandbox, bytes.NewReader(encoded)))
+	for index := range numEntities / 10 {
+		assert.Equal(suite.T(), velocity{X: float64(index), Y: -float64(index)}, *suite.velocityLinker.Get(entity.Id(index)), componentValueMsg, velocityComponent, index)
+	}
+	suite.assertDeletedEntity(numEntities/10, entityNotUnlinkedMsg, numEntities/10)
+}
+
+// olderVelocity has the layout of the older velocity saved by saveOlderVelocity.
+type olderVelocity struct {
+	Speed float64
+}
+
+// saveOlderVelocity saves a binary snapshot of an older velocity layout, registered under the same type name.
+func saveOlderVelocity(t *testing.T, mode options.Mode, poolSize uint) []byte {
+	type velocity olderVelocity
+
+	older := sandbox.New(mode, options.DefaultNumEntities, options.DefaultNumComponents, poolSize)
+	olderLinker := sandbox.ComponentLinker[velocity](older)
+	for index := range numEntities / 10 {
+		olderLinker.Link(sandbox.LinkEntity(older)).Speed = float64(index)
+	}
+	sandbox.Update(older)
+
+	var buffer bytes.Buffer
+	assert.NoError(t, sandbox.Save(older, &buffer, snapshot.Binary))
+	return buffer.Bytes()
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_BinaryTruncated() {
+	encoded := suite.save(snapshot.Binary).Bytes()
+	assert.ErrorIs(suite.T(), sandbox.Load(suite.sandbox, bytes.NewReader(encoded[:len(encoded)/2])), snapshot.ErrInvalidSnapshot)
+	suite.assertEntity(1, entityNotLinkedMsg, 1)
+}
+
+func (suite *SnapshotTestSuite) TestSnapshot_BinaryOversizedLengths() {
+	header := append([]byte("SDCS"), 1, 0)
+	huge := binary.AppendUvarint(nil, 1<<32)
+	for name, encoded := range map[string][]byte{
+		"column count": append(slices.Clone(header), huge...),
+		"name length":  append(append(slices.Clone(header), 1), huge...),
+		"mask length":  append(slices.Clone(header), 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0),
+	} {
+		// Lengths beyond the input fail without allocating them
+		assert.ErrorIs(suite.T(), sandbox.Load(suite.sandbox, bytes.NewReader(encoded)), snapshot.ErrInvalidSnapshot, name)
+	}
+	suite.assertEntity(1, entityNotLinkedMsg, 1)
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSortedSuite(t *testing.T) {
+	suite.Run(t, &SortedTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &SortedTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &SortedTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type SortedTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	view           filter.View
+	sorted         filter.SortedView
+}
+
+func (suite *SortedTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.view = sandbox.Filter(suite.sandbox, filter.Match[position]())
+	suite.sorted = sandbox.SortedView(suite.sandbox, suite.view, func(a, b *position) bool {
+		return a.Y < b.Y
+	})
+}
+
+func (suite *SortedTestSuite) TestSorted_Order() {
+	random := rand.New(rand.NewSource(1))
+	for range 1000 {
+		suite.link(random)
+	}
+	sandbox.Update(suite.sandbox)
+	suite.assertSorted()
+
+	// Entities exiting and entering the view
+	for index, entityId := range suite.view.EntityIds() {
+		if index%7 == 0 {
+			suite.positionLinker.Unlink(entityId)
+		}
+	}
+	for range 10 {
+		suite.link(random)
+	}
+	sandbox.Update(suite.sandbox)
+	suite.assertSorted()
+
+	// Entities moving after their component changed (the order is synchronized once per update)
+	order := slices.Clone(suite.sorted.EntityIds())
+	for index, entityId := range suite.view.EntityIds() {
+		if index%50 == 0 {
+			suite.positionLinker.Get(entityId).Y = float64(random.Intn(100) + 100)
+			suite.positionLinker.MarkChanged(entityId)
+		}
+	}
+	assert.Equal(suite.T(), order, suite.sorted.EntityIds())
+	sandbox.Update(suite.sandbox)
+	suite.assertSorted()
+}
+
+func (suite *SortedTestSuite) TestSorted_UnmarkedChangesKeepPosition() {
+	first := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(first).Y = 1
+	second := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(second).Y = 2
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{first, second}, suite.sorted.EntityIds())
+
+	suite.positionLinker.Get(first).Y = 3
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{first, second}, suite.sorted.EntityIds())
+
+	suite.positionLinker.MarkChanged(first)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{second, first}, suite.sorted.EntityIds())
+	assert.Equal(suite.T(), suite.view.EntityMask(), suite.sorted.EntityMask())
+}
+
+func (suite *SortedTestSuite) TestSorted_Release() {
+	first := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(first).Y = 1
+	second := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(second).Y = 2
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{first, second}, suite.sorted.EntityIds())
+
+	// Released views are no longer updated
+	suite.sorted.Release()
+	suite.sorted.Release()
+	suite.positionLinker.Get(first).Y = 3
+	suite.positionLinker.MarkChanged(first)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{first, second}, suite.sorted.EntityIds())
+}
+
+func (suite *SortedTestSuite) link(random *rand.Rand) {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(entityId).Y = float64(random.Intn(100))
+}
+
+func (suite *SortedTestSuite) assertSorted() {
+	expected := slices.Clone(suite.view.EntityIds())
+	slices.SortFunc(expected, func(a, b entity.Id) int {
+		return cmp.Or(cmp.Compare(suite.positionLinker.Get(a).Y, suite.positionLinker.Get(b).Y), cmp.Compare(a, b))
+	})
+	assert.Equal(suite.T(), expected, suite.sorted.EntityIds())
+}
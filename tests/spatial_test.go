@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/andrei-cosmin/sandecs/spatial"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSpatialSuite(t *testing.T) {
+	suite.Run(t, &SpatialTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &SpatialTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &SpatialTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type SpatialTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	grid           *spatial.Grid
+	random         *rand.Rand
+}
+
+func (suite *SpatialTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.grid = spatial.New(suite.sandbox, 10, func(component *position) spatial.Point {
+		return spatial.Point{X: component.X, Y: component.Y}
+	})
+	suite.random = rand.New(rand.NewSource(1))
+}
+
+func (suite *SpatialTestSuite) TestSpatial_Queries() {
+	for range 500 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		*suite.positionLinker.Link(entityId) = suite.randomPosition()
+	}
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), 500, suite.grid.Len())
+	suite.assertQueries()
+
+	// Moved entities are re-indexed once marked changed, removed entities are dropped
+	for index, entityId := range sandbox.Entities(suite.sandbox) {
+		switch index % 5 {
+		case 0:
+			*suite.positionLinker.Get(entityId) = suite.randomPosition()
+			suite.positionLinker.MarkChanged(entityId)
+		case 1:
+			sandbox.UnlinkEntity(suite.sandbox, entityId)
+		}
+	}
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), 400, suite.grid.Len())
+	suite.assertQueries()
+}
+
+func (suite *SpatialTestSuite) TestSpatial_Within() {
+	healthLinker := sandbox.ComponentLinker[health](suite.sandbox)
+	view := sandbox.Filter(suite.sandbox, filter.Match[health]())
+	defer view.Release()
+
+	near := sandbox.LinkEntity(suite.sandbox)
+	*suite.positionLinker.Link(near) = position{X: 1, Y: 1}
+	healthLinker.Link(near)
+	other := sandbox.LinkEntity(suite.sandbox)
+	*suite.positionLinker.Link(other) = position{X: 2, Y: 2}
+	sandbox.Update(suite.sandbox)
+
+	found := suite.grid.QueryRadius(spatial.Point{}, 5)
+	assert.Equal(suite.T(), []entity.Id{near, other}, found)
+	assert.Equal(suite.T(), []entity.Id{near}, spatial.Within(found, view))
+}
+
+func (suite *SpatialTestSuite) TestSpatial_FarQueries() {
+	near := sandbox.LinkEntity(suite.sandbox)
+	*suite.positionLinker.Link(near) = position{X: 1, Y: 1}
+	far := sandbox.LinkEntity(suite.sandbox)
+	*suite.positionLinker.Link(far) = position{X: 1e9, Y: -1e9}
+	sandbox.Update(suite.sandbox)
+
+	// Queries far from the entities, or spanning sparse bounds, don't walk the empty cells
+	assert.Equal(suite.T(), []entity.Id{near, far}, suite.grid.Nearest(spatial.Point{}, 2))
+	assert.Equal(suite.T(), []entity.Id{far, near}, suite.grid.Nearest(spatial.Point{X: 2e9, Y: -2e9}, 5))
+	assert.Equal(suite.T(), []entity.Id{near}, suite.grid.Nearest(spatial.Point{X: -1e300, Y: 1e300}, 1))
+	assert.Equal(suite.T(), []entity.Id{near, far}, suite.grid.QueryAABB(spatial.Point{X: -1e300, Y: -1e300}, spatial.Point{X: 1e300, Y: 1e300}))
+
+	// Once the far entity is removed, the bounds shrink back
+	sandbox.UnlinkEntity(suite.sandbox, far)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{near}, suite.grid.Nearest(spatial.Point{X: 5e8, Y: 5e8}, 3))
+}
+
+func (suite *SpatialTestSuite) TestSpatial_NonFinitePositions() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	*suite.positionLinker.Link(entityId) = position{X: math.NaN(), Y: 1}
+	other := sandbox.LinkEntity(suite.sandbox)
+	*suite.positionLinker.Link(other) = position{X: 1, Y: math.Inf(1)}
+	sandbox.Update(suite.sandbox)
+
+	// Entities at non-finite positions aren't indexed, and non-finite queries find nothing
+	assert.Zero(suite.T(), suite.grid.Len())
+	_, ok := suite.grid.Position(entityId)
+	assert.False(suite.T(), ok)
+	assert.Nil(suite.T(), suite.grid.Nearest(spatial.Point{X: math.NaN()}, 1))
+
+	*suite.positionLinker.Get(entityId) = position{X: 1, Y: 1}
+	suite.positionLinker.MarkChanged(entityId)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []entity.Id{entityId}, suite.grid.Nearest(spatial.Point{}, 2))
+	assert.Nil(suite.T(), suite.grid.Nearest(spatial.Point{Y: math.Inf(-1)}, 1))
+	assert.Empty(suite.T(), suite.grid.QueryRadius(spatial.Point{X: math.NaN()}, 10))
+	assert.Empty(suite.T(), suite.grid.QueryAABB(spatial.Point{X: math.Inf(-1)}, spatial.Point{X: 10, Y: 10}))
+
+	// Moving an indexed entity to a non-finite position drops it
+	*suite.positionLinker.Get(entityId) = position{X: math.NaN(), Y: math.NaN()}
+	suite.positionLinker.MarkChanged(entityId)
+	sandbox.Update(suite.sandbox)
+	assert.Zero(suite.T(), suite.grid.Len())
+}
+
+func (suite *SpatialTestSuite) TestSpatial_Release() {
+	changes, unlinks := 0, 0
+	observer := sandbox.Observe(suite.sandbox, func(entity.Id, *position) {
+		changes++
+	}, func(entity.Id) {
+		unlinks++
+	})
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(entityId)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), 1, changes)
+	assert.Equal(suite.T(), 1, suite.grid.Len())
+
+	// Released observers (and grids) are no longer updated
+	observer.Release()
+	observer.Release()
+	suite.grid.Release()
+	sandbox.UnlinkEntity(suite.sandbox, entityId)
+	suite.positionLinker.Link(sandbox.LinkEntity(suite.sandbox))
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), 1, changes)
+	assert.Zero(suite.T(), unlinks)
+	assert.Equal(suite.T(), 1, suite.grid.Len())
+}
+
+func (suite *SpatialTestSuite) randomPosition() position {
+	return position{X: suite.random.Float64()*400 - 200, Y: suite.random.Float64()*400 - 200}
+}
+
+func (suite *SpatialTestSuite) assertQueries() {
+	for range 20 {
+		center := suite.randomPosition()
+		point := spatial.Point{X: center.X, Y: center.Y}
+		radius := suite.random.Float64() * 50
+
+		var inRadius, inBox []entity.Id
+		all := make([]entity.Id, 0)
+		for _, entityId := range sandbox.Entities(suite.sandbox) {
+			current := suite.positionLinker.Get(entityId)
+			dx, dy := current.X-center.X, current.Y-center.Y
+			if dx*dx+dy*dy <= radius*radius {
+				inRadius = append(inRadius, entityId)
+			}
+			if dx >= -radius && dx <= radius/2 && dy >= -radius && dy <= radius/2 {
+				inBox = append(inBox, entityId)
+			}
+			all = append(all, entityId)
+		}
+		slices.Sort(inRadius)
+		slices.Sort(inBox)
+
+		assert.ElementsMatch(suite.T(), inRadius, suite.grid.QueryRadius(point, radius))
+		assert.ElementsMatch(suite.T(), inBox, suite.grid.QueryAABB(
+			spatial.Point{X: center.X - radius, Y: center.Y - radius},
+			spatial.Point{X: center.X + radius/2, Y: center.Y + radius/2},
+		))
+
+		distance := func(entityId entity.Id) float64 {
+			current := suite.positionLinker.Get(entityId)
+			return (current.X-center.X)*(current.X-center.X) + (current.Y-center.Y)*(current.Y-center.Y)
+		}
+		slices.SortFunc(all, func(a, b entity.Id) int {
+			return cmp.Or(cmp.Compare(distance(a), distance(b)), cmp.Compare(a, b))
+		})
+		assert.Equal(suite.T(), all[:5], suite.grid.Nearest(point, 5))
+	}
+}
@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/inspect"
+	"github.com/andrei-cosmin/sandecs/instrument"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestStatsSuite(t *testing.T) {
+	suite.Run(t, &StatsTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &StatsTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &StatsTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type StatsTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	renderedLinker component.TagLinker
+	frames         []inspect.Stats
+}
+
+func (suite *StatsTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+	sandbox.Filter(suite.sandbox, filter.Match[position]())
+	suite.frames = nil
+	sandbox.Instrument(suite.sandbox, inspect.InstrumentationFunc(func(stats inspect.Stats) {
+		suite.frames = append(suite.frames, stats)
+	}))
+}
+
+func (suite *StatsTestSuite) TestStats_CountsPerFrame() {
+	entityIds := make([]uint, 0, 3)
+	for range 3 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId)
+		entityIds = append(entityIds, entityId)
+	}
+	suite.renderedLinker.Link(entityIds[0])
+	sandbox.Update(suite.sandbox)
+
+	stats := sandbox.Stats(suite.sandbox)
+	assert.Equal(suite.T(), uint64(1), stats.Frame)
+	assert.Equal(suite.T(), uint(3), stats.EntitiesLinked)
+	assert.Equal(suite.T(), uint(0), stats.EntitiesUnlinked)
+	assert.Equal(suite.T(), []inspect.ComponentStats{
+		{Id: 0, Name: testsPath + "position", Linked: 3},
+		{Id: 1, Name: renderedComponent, Linked: 1},
+	}, stats.Components)
+	assert.Equal(suite.T(), uint(1), stats.FiltersRecomputed)
+	assert.Equal(suite.T(), uint(1), stats.FiltersChanged)
+	assert.Equal(suite.T(), stats.Phases.Entities+stats.Phases.Components+stats.Phases.Filters, stats.Phases.Total())
+
+	// Unlinking an entity unlinks its components too
+	sandbox.UnlinkEntity(suite.sandbox, entityIds[0])
+	suite.positionLinker.Unlink(entityIds[1])
+	sandbox.LinkEntity(suite.sandbox)
+	sandbox.Update(suite.sandbox)
+
+	stats = sandbox.Stats(suite.sandbox)
+	assert.Equal(suite.T(), uint64(2), stats.Frame)
+	assert.Equal(suite.T(), uint(1), stats.EntitiesLinked)
+	assert.Equal(suite.T(), uint(1), stats.EntitiesUnlinked)
+	assert.Equal(suite.T(), []inspect.ComponentStats{
+		{Id: 0, Name: testsPath + "position", Unlinked: 2},
+		{Id: 1, Name: renderedComponent, Unlinked: 1},
+	}, stats.Components)
+	assert.Equal(suite.T(), uint(1), stats.FiltersChanged)
+
+	// Frames without pending changes are still recorded
+	sandbox.Update(suite.sandbox)
+	stats = sandbox.Stats(suite.sandbox)
+	assert.Equal(suite.T(), uint64(3), stats.Frame)
+	assert.Zero(suite.T(), stats.EntitiesLinked+stats.EntitiesUnlinked+stats.FiltersRecomputed)
+	assert.Zero(suite.T(), stats.Phases.Total())
+
+	if assert.Len(suite.T(), suite.frames, 3) {
+		assert.Equal(suite.T(), stats, suite.frames[2])
+	}
+}
+
+func (suite *StatsTestSuite) TestStats_Slog() {
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buffer, nil))
+	sandbox.Instrument(suite.sandbox, instrument.Slog(logger, slog.LevelInfo))
+	sandbox.Instrument(suite.sandbox, instrument.Slog(logger, slog.LevelDebug))
+
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(entityId)
+	sandbox.Update(suite.sandbox)
+
+	// Only the info level record is enabled
+	var record map[string]any
+	suite.Require().NoError(json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(suite.T(), "sandbox frame", record["msg"])
+	assert.Equal(suite.T(), 1.0, record["frame"])
+	assert.Equal(suite.T(), map[string]any{"linked": 1.0, "unlinked": 0.0}, record["entities"])
+	assert.Equal(suite.T(), map[string]any{testsPath + "position": map[string]any{"linked": 1.0, "unlinked": 0.0}}, record["components"])
+	assert.Contains(suite.T(), record, "phases")
+}
+
+func (suite *StatsTestSuite) TestStats_Expvar() {
+	name := fmt.Sprintf("sandecs-stats-%d", suite.mode)
+	sandbox.Instrument(suite.sandbox, instrument.Expvar(name))
+
+	for range 2 {
+		sandbox.LinkEntity(suite.sandbox)
+		sandbox.Update(suite.sandbox)
+	}
+
+	var vars map[string]any
+	suite.Require().NoError(json.Unmarshal([]byte(expvar.Get(name).String()), &vars))
+	assert.Equal(suite.T(), 2.0, vars["frames"])
+	assert.Equal(suite.T(), 2.0, vars["entitiesLinked"])
+	assert.Equal(suite.T(), 2.0, vars["lastFrame"].(map[string]any)["Frame"])
+	assert.Panics(suite.T(), func() { instrument.Expvar(name) })
+}
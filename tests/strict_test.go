@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestStrictSuite(t *testing.T) {
+	suite.Run(t, &StrictTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &StrictTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &StrictTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type StrictTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	renderedLinker component.TagLinker
+}
+
+func (suite *StrictTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize, options.Strict)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+}
+
+// assertPanicsWith checks that the function panics with an error matching the target (and the component name, if any).
+func (suite *StrictTestSuite) assertPanicsWith(target error, componentName string, function func()) {
+	defer func() {
+		err, ok := recover().(error)
+		if !assert.True(suite.T(), ok, "expected a panic with an error") {
+			return
+		}
+		assert.ErrorIs(suite.T(), err, target)
+		var linkError *component.LinkError
+		if componentName != "" && assert.True(suite.T(), errors.As(err, &linkError)) {
+			assert.Equal(suite.T(), componentName, linkError.Component)
+			assert.Contains(suite.T(), err.Error(), componentName)
+		}
+	}()
+	function()
+}
+
+func (suite *StrictTestSuite) TestStrict_ComponentMisuse() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(entityId)
+
+	suite.assertPanicsWith(component.ErrEntityNotLinked, testsPath+"position", func() { suite.positionLinker.Link(entityId + 1) })
+	suite.assertPanicsWith(component.ErrAlreadyLinked, testsPath+"position", func() { suite.positionLinker.Link(entityId) })
+
+	otherId := sandbox.LinkEntity(suite.sandbox)
+	suite.assertPanicsWith(component.ErrNotLinked, testsPath+"position", func() { suite.positionLinker.Get(otherId) })
+	suite.assertPanicsWith(component.ErrNotLinked, testsPath+"position", func() { suite.positionLinker.Unlink(otherId) })
+	suite.assertPanicsWith(component.ErrNotLinked, testsPath+"position", func() { suite.positionLinker.MarkChanged(otherId) })
+
+	assert.True(suite.T(), suite.positionLinker.Unlink(entityId))
+	suite.assertPanicsWith(component.ErrAlreadyUnlinked, testsPath+"position", func() { suite.positionLinker.Unlink(entityId) })
+
+	// Linking to an entity scheduled for removal would be dropped on the next update
+	sandbox.UnlinkEntity(suite.sandbox, otherId)
+	suite.assertPanicsWith(component.ErrStaleEntity, testsPath+"position", func() { suite.positionLinker.Link(otherId) })
+	suite.assertPanicsWith(component.ErrStaleEntity, "", func() { sandbox.UnlinkEntity(suite.sandbox, otherId) })
+
+	sandbox.Update(suite.sandbox)
+	suite.assertPanicsWith(component.ErrEntityNotLinked, testsPath+"position", func() { suite.positionLinker.Get(otherId) })
+	suite.assertPanicsWith(component.ErrEntityNotLinked, "", func() { sandbox.UnlinkEntity(suite.sandbox, otherId) })
+}
+
+func (suite *StrictTestSuite) TestStrict_TagMisuse() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	assert.True(suite.T(), suite.renderedLinker.Link(entityId))
+
+	suite.assertPanicsWith(component.ErrAlreadyLinked, renderedComponent, func() { suite.renderedLinker.Link(entityId) })
+	suite.assertPanicsWith(component.ErrEntityNotLinked, renderedComponent, func() { suite.renderedLinker.Unlink(entityId + 1) })
+}
+
+func (suite *StrictTestSuite) TestStrict_FilterAfterStart() {
+	sandbox.Filter(suite.sandbox, filter.Match[position]())
+	sandbox.Update(suite.sandbox)
+
+	suite.assertPanicsWith(filter.ErrFilterAfterStart, "", func() { sandbox.Filter(suite.sandbox, filter.Match[position]()) })
+}
+
+func (suite *StrictTestSuite) TestStrict_LoadSnapshot() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	suite.positionLinker.Link(entityId).X = 3
+	suite.renderedLinker.Link(entityId)
+	sandbox.Update(suite.sandbox)
+
+	var buffer bytes.Buffer
+	suite.Require().NoError(sandbox.Save(suite.sandbox, &buffer, snapshot.Binary))
+	suite.Require().NoError(sandbox.Load(suite.sandbox, &buffer))
+	assert.Equal(suite.T(), position{X: 3}, *suite.positionLinker.Get(entityId))
+	assert.True(suite.T(), suite.renderedLinker.Has(entityId))
+}
+
+func (suite *StrictTestSuite) TestStrict_DisabledByDefault() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	positionLinker := sandbox.ComponentLinker[position](suite.sandbox)
+	entityId := sandbox.LinkEntity(suite.sandbox)
+
+	assert.NotPanics(suite.T(), func() {
+		assert.Nil(suite.T(), positionLinker.Link(entityId+1))
+		assert.NotNil(suite.T(), positionLinker.Link(entityId))
+		assert.Nil(suite.T(), positionLinker.Link(entityId))
+		assert.False(suite.T(), positionLinker.Unlink(entityId+1))
+		sandbox.UnlinkEntity(suite.sandbox, entityId+1)
+		sandbox.Update(suite.sandbox)
+		sandbox.Filter(suite.sandbox, filter.Match[position]())
+	})
+}
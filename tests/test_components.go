@@ -1,6 +1,14 @@
 package tests
 
+import (
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+)
+
 const (
+	// testsPath is the package path qualifying the default names of the test components
+	testsPath = "github.com/andrei-cosmin/sandecs/tests."
+
 	numEntities = 10000
 	numRemoves  = 1000
 
@@ -52,3 +60,36 @@ type rendered struct {
 type armor struct {
 	value int
 }
+
+type follower struct {
+	Target  component.Ref
+	Escorts []component.Ref
+}
+
+type parent struct {
+	Id entity.Id
+}
+
+func (p *parent) RemapEntities(remap func(entity.Id) (entity.Id, bool)) {
+	p.Id, _ = remap(p.Id)
+}
+
+type inventory struct {
+	Items []string
+}
+
+func (i *inventory) Clone() inventory {
+	return inventory{Items: append([]string(nil), i.Items...)}
+}
+
+type stats struct {
+	Level  uint8
+	Score  int64
+	Speed  velocity
+	Tags   []string
+	secret int
+}
+
+type selected struct{}
+
+type hidden struct{}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTrySuite(t *testing.T) {
+	suite.Run(t, &TryTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &TryTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &TryTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type TryTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	renderedLinker component.TagLinker
+}
+
+func (suite *TryTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+}
+
+func (suite *TryTestSuite) TestTry_ComponentLinker() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+
+	_, err := suite.positionLinker.TryLink(entityId + 1)
+	assert.ErrorIs(suite.T(), err, component.ErrEntityNotLinked)
+
+	instance, err := suite.positionLinker.TryLink(entityId)
+	assert.NoError(suite.T(), err)
+	instance.X = 1
+	assert.Equal(suite.T(), position{X: 1}, *suite.positionLinker.Get(entityId))
+
+	_, err = suite.positionLinker.TryLink(entityId)
+	assert.ErrorIs(suite.T(), err, component.ErrAlreadyLinked)
+	var linkError *component.LinkError
+	if assert.True(suite.T(), errors.As(err, &linkError)) {
+		assert.Equal(suite.T(), testsPath+"position", linkError.Component)
+		assert.Equal(suite.T(), entityId, linkError.Entity)
+	}
+
+	assert.NoError(suite.T(), suite.positionLinker.TryUnlink(entityId))
+	assert.ErrorIs(suite.T(), suite.positionLinker.TryUnlink(entityId), component.ErrAlreadyUnlinked)
+	sandbox.Update(suite.sandbox)
+	assert.ErrorIs(suite.T(), suite.positionLinker.TryUnlink(entityId), component.ErrNotLinked)
+	assert.ErrorIs(suite.T(), suite.positionLinker.TryUnlink(entityId+1), component.ErrEntityNotLinked)
+
+	// TryLink rejects entities scheduled for removal, Link only does in strict mode (the link is dropped on the next update)
+	sandbox.UnlinkEntity(suite.sandbox, entityId)
+	_, err = suite.positionLinker.TryLink(entityId)
+	assert.ErrorIs(suite.T(), err, component.ErrStaleEntity)
+	assert.ErrorIs(suite.T(), suite.renderedLinker.TryLink(entityId), component.ErrStaleEntity)
+	assert.False(suite.T(), suite.positionLinker.Has(entityId))
+	assert.NotNil(suite.T(), suite.positionLinker.Link(entityId))
+	assert.True(suite.T(), suite.renderedLinker.Link(entityId))
+	sandbox.Update(suite.sandbox)
+	assert.False(suite.T(), suite.positionLinker.Has(entityId))
+}
+
+func (suite *TryTestSuite) TestTry_TagLinker() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+	linked := 0
+	suite.renderedLinker.SetLinkHook(func() { linked++ })
+
+	assert.ErrorIs(suite.T(), suite.renderedLinker.TryLink(entityId+1), component.ErrEntityNotLinked)
+	assert.NoError(suite.T(), suite.renderedLinker.TryLink(entityId))
+	assert.ErrorIs(suite.T(), suite.renderedLinker.TryLink(entityId), component.ErrAlreadyLinked)
+	assert.Equal(suite.T(), 1, linked)
+
+	assert.NoError(suite.T(), suite.renderedLinker.TryUnlink(entityId))
+	assert.ErrorIs(suite.T(), suite.renderedLinker.TryUnlink(entityId), component.ErrAlreadyUnlinked)
+}
+
+func (suite *TryTestSuite) TestTry_UnlinkEntity() {
+	entityId := sandbox.LinkEntity(suite.sandbox)
+
+	assert.ErrorIs(suite.T(), sandbox.TryUnlinkEntity(suite.sandbox, entityId+1), component.ErrEntityNotLinked)
+	assert.NoError(suite.T(), sandbox.TryUnlinkEntity(suite.sandbox, entityId))
+	assert.ErrorIs(suite.T(), sandbox.TryUnlinkEntity(suite.sandbox, entityId), component.ErrStaleEntity)
+	sandbox.Update(suite.sandbox)
+	suite.assertDeletedEntity(entityId)
+	assert.ErrorIs(suite.T(), sandbox.TryUnlinkEntity(suite.sandbox, entityId), component.ErrEntityNotLinked)
+
+	// Outside strict mode, failed unlinks are ignored without building an error
+	allocations := testing.AllocsPerRun(100, func() {
+		sandbox.UnlinkEntity(suite.sandbox, entityId)
+	})
+	assert.Zero(suite.T(), allocations)
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/entity"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTypedQuerySuite(t *testing.T) {
+	suite.Run(t, &TypedQueryTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &TypedQueryTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &TypedQueryTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type TypedQueryTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	healthLinker   component.Linker[health]
+	armorLinker    component.Linker[armor]
+}
+
+func (suite *TypedQueryTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize, options.Strict)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.healthLinker = sandbox.ComponentLinker[health](suite.sandbox)
+	suite.armorLinker = sandbox.ComponentLinker[armor](suite.sandbox)
+
+	for index := range 100 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId).X = float64(index)
+		if index%2 == 0 {
+			suite.healthLinker.Link(entityId).value = float64(index)
+		}
+		if index%3 == 0 {
+			suite.armorLinker.Link(entityId).value = index
+		}
+	}
+}
+
+func (suite *TypedQueryTestSuite) TestTypedQuery_Required() {
+	query := sandbox.NewQuery2[position, health](suite.sandbox, filter.Exclude[armor]())
+	defer query.Release()
+	sandbox.Update(suite.sandbox)
+
+	var entityIds []entity.Id
+	query.Each(func(entityId entity.Id, position *position, health *health) {
+		assert.Equal(suite.T(), position.X, health.value)
+		entityIds = append(entityIds, entityId)
+	})
+	assert.Equal(suite.T(), sandbox.QueryOnce(suite.sandbox, filter.Match2[position, health](), filter.Exclude[armor]()), entityIds)
+}
+
+func (suite *TypedQueryTestSuite) TestTypedQuery_Optional() {
+	query := sandbox.NewQuery3[position, health, armor](suite.sandbox, filter.Optional[health](), filter.Optional[armor]())
+	defer query.Release()
+	sandbox.Update(suite.sandbox)
+
+	// Entities without the optional components are still matched, with nil components (no strict mode failures)
+	count := 0
+	query.Each(func(entityId entity.Id, position *position, health *health, armor *armor) {
+		assert.NotNil(suite.T(), position)
+		assert.Equal(suite.T(), suite.healthLinker.Has(entityId), health != nil)
+		assert.Equal(suite.T(), suite.armorLinker.Has(entityId), armor != nil)
+		if armor != nil {
+			assert.Equal(suite.T(), int(position.X), armor.value)
+		}
+		count++
+	})
+	assert.Equal(suite.T(), 100, count)
+	assert.Equal(suite.T(), uint(100), query.View().Count())
+}
+
+func (suite *TypedQueryTestSuite) TestTypedQuery_SharesFilters() {
+	first := sandbox.NewQuery1[health](suite.sandbox)
+	defer first.Release()
+	second := sandbox.NewQuery2[health, armor](suite.sandbox, filter.Optional[armor]())
+	defer second.Release()
+
+	// Both queries match entities with health only
+	assert.Len(suite.T(), sandbox.Filters(suite.sandbox), 1)
+}
+
+func (suite *TypedQueryTestSuite) TestTypedQuery_Access() {
+	query := sandbox.NewQuery2[position, health](suite.sandbox, filter.Read[position](), filter.Write[health]())
+	defer query.Release()
+	reader := sandbox.NewQuery1[position](suite.sandbox, filter.Read[position]())
+	defer reader.Release()
+	other := sandbox.NewQuery1[health](suite.sandbox)
+	defer other.Release()
+	sandbox.Update(suite.sandbox)
+
+	changed := make(map[entity.Id]bool)
+	sandbox.Observe(suite.sandbox, func(entityId entity.Id, _ *health) {
+		changed[entityId] = true
+	}, func(entity.Id) {})
+	clear(changed)
+
+	// Read components are copies, written components are marked changed
+	query.Each(func(entityId entity.Id, position *position, health *health) {
+		position.X = -1
+		health.value = -1
+	})
+	sandbox.Update(suite.sandbox)
+	for _, entityId := range query.View().EntityIds() {
+		assert.Equal(suite.T(), float64(entityId), suite.positionLinker.Get(entityId).X)
+		assert.Equal(suite.T(), -1.0, suite.healthLinker.Get(entityId).value)
+		assert.True(suite.T(), changed[entityId])
+	}
+	assert.Len(suite.T(), changed, int(query.View().Count()))
+
+	healthId, positionId := suite.healthLinker.ComponentId(), suite.positionLinker.ComponentId()
+	assert.Equal(suite.T(), []filter.Access{{ComponentId: positionId, Write: false}, {ComponentId: healthId, Write: true}}, query.Access())
+
+	// Undeclared components are reported as written
+	assert.True(suite.T(), filter.Conflicts(query.Access(), other.Access()))
+	assert.False(suite.T(), filter.Conflicts(query.Access(), reader.Access()))
+}
+
+func (suite *TypedQueryTestSuite) TestTypedQuery_EmptyQuery() {
+	// Queries without match, exclude or union rules would never match any entity
+	assert.PanicsWithValue(suite.T(), filter.ErrEmptyQuery, func() {
+		sandbox.NewQuery2[position, health](suite.sandbox, filter.Optional[position](), filter.Optional[health]())
+	})
+	assert.PanicsWithValue(suite.T(), filter.ErrEmptyQuery, func() {
+		sandbox.NewQuery1[health](suite.sandbox, filter.Optional[health](), filter.Read[health]())
+	})
+
+	// Exclude-only queries match every entity without the excluded components
+	query := sandbox.NewQuery2[position, health](suite.sandbox, filter.Optional[position](), filter.Optional[health](), filter.Exclude[armor]())
+	defer query.Release()
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), sandbox.QueryOnce(suite.sandbox, filter.Exclude[armor]()), query.View().EntityIds())
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/inspect"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/andrei-cosmin/sandecs/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTypedTagSuite(t *testing.T) {
+	suite.Run(t, &TypedTagTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &TypedTagTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &TypedTagTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type TypedTagTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	selectedLinker component.TagLinker
+	hiddenLinker   component.TagLinker
+}
+
+func (suite *TypedTagTestSuite) SetupTest() {
+	suite.sandbox = suite.newSandbox()
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.selectedLinker = sandbox.TypedTagLinker[selected](suite.sandbox)
+	suite.hiddenLinker = sandbox.TypedTagLinker[hidden](suite.sandbox)
+}
+
+func (suite *TypedTagTestSuite) newSandbox() *sandbox.Sandbox {
+	return sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize)
+}
+
+func (suite *TypedTagTestSuite) TestTypedTag_Filters() {
+	visible := sandbox.Filter(suite.sandbox, filter.Match[position](), filter.ExcludeTag[hidden]())
+	picked := sandbox.Filter(suite.sandbox, filter.MatchTag[selected]())
+	either := sandbox.Filter(suite.sandbox, filter.UnionTag[selected](), filter.UnionTag[hidden]())
+
+	for index := range 6 {
+		entityId := sandbox.LinkEntity(suite.sandbox)
+		suite.positionLinker.Link(entityId)
+		if index%2 == 0 {
+			assert.True(suite.T(), suite.selectedLinker.Link(entityId))
+		}
+		if index%3 == 0 {
+			assert.True(suite.T(), suite.hiddenLinker.Link(entityId))
+		}
+	}
+	assert.False(suite.T(), suite.selectedLinker.Link(0))
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []uint{1, 2, 4, 5}, visible.EntityIds())
+	assert.Equal(suite.T(), []uint{0, 2, 4}, picked.EntityIds())
+	assert.Equal(suite.T(), []uint{0, 2, 3, 4}, either.EntityIds())
+
+	suite.selectedLinker.Unlink(2)
+	sandbox.Update(suite.sandbox)
+	assert.Equal(suite.T(), []uint{0, 4}, picked.EntityIds())
+}
+
+func (suite *TypedTagTestSuite) TestTypedTag_Registration() {
+	assert.Equal(suite.T(), suite.selectedLinker.ComponentId(), sandbox.TypedTagLinker[selected](suite.sandbox).ComponentId())
+
+	// Typed tags are named after their type, a name shared with string tags
+	assert.Equal(suite.T(), suite.selectedLinker.ComponentId(), sandbox.TagLinker(suite.sandbox, testsPath+"selected").ComponentId())
+	descriptors := sandbox.Components(suite.sandbox)
+	if assert.Len(suite.T(), descriptors, 3) {
+		assert.Equal(suite.T(), inspect.Component{Id: 1, Name: testsPath + "selected", Kind: inspect.TagKind}, descriptors[1])
+	}
+
+	// Typed tags and components are separate, even for the same type
+	assert.NotEqual(suite.T(), suite.selectedLinker.ComponentId(), sandbox.ComponentLinker[selected](suite.sandbox).ComponentId())
+
+	assert.PanicsWithError(suite.T(), "component: typed tags must be zero-sized structs: tests.position", func() {
+		sandbox.TypedTagLinker[position](suite.sandbox)
+	})
+	assert.Panics(suite.T(), func() {
+		sandbox.TypedTagLinker[[0]int](suite.sandbox)
+	})
+}
+
+func (suite *TypedTagTestSuite) TestTypedTag_Snapshot() {
+	first, second := sandbox.LinkEntity(suite.sandbox), sandbox.LinkEntity(suite.sandbox)
+	suite.selectedLinker.Link(first)
+	suite.hiddenLinker.Link(second)
+	sandbox.Update(suite.sandbox)
+
+	var buffer bytes.Buffer
+	suite.Require().NoError(sandbox.Save(suite.sandbox, &buffer, snapshot.Binary))
+
+	// Typed tags registered after loading refer to the loaded tags
+	loaded := suite.newSandbox()
+	sandbox.ComponentLinker[position](loaded)
+	suite.Require().NoError(sandbox.Load(loaded, &buffer))
+	assert.True(suite.T(), sandbox.TypedTagLinker[selected](loaded).Has(first))
+	assert.False(suite.T(), sandbox.TypedTagLinker[selected](loaded).Has(second))
+	assert.True(suite.T(), sandbox.TypedTagLinker[hidden](loaded).Has(second))
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/andrei-cosmin/sandecs"
+	"github.com/andrei-cosmin/sandecs/component"
+	"github.com/andrei-cosmin/sandecs/filter"
+	"github.com/andrei-cosmin/sandecs/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestValidateSuite(t *testing.T) {
+	suite.Run(t, &ValidateTestSuite{mode: options.Standard, poolSize: 0})
+	suite.Run(t, &ValidateTestSuite{mode: options.Pooled, poolSize: numEntities / 2})
+	suite.Run(t, &ValidateTestSuite{mode: options.Compact, poolSize: 0})
+}
+
+type ValidateTestSuite struct {
+	sandboxSuite
+	mode           options.Mode
+	poolSize       uint
+	positionLinker component.Linker[position]
+	velocityLinker component.Linker[velocity]
+	renderedLinker component.TagLinker
+}
+
+func (suite *ValidateTestSuite) SetupTest() {
+	suite.sandbox = sandbox.New(suite.mode, options.DefaultNumEntities, options.DefaultNumComponents, suite.poolSize, options.Validate)
+	suite.positionLinker = sandbox.ComponentLinker[position](suite.sandbox)
+	suite.velocityLinker = sandbox.ComponentLinker[velocity](suite.sandbox)
+	suite.renderedLinker = sandbox.TagLinker(suite.sandbox, renderedComponent)
+	sandbox.Filter(suite.sandbox, filter.Match[position](), filter.Exclude[velocity]())
+	sandbox.Filter(suite.sandbox, filter.UnionTags(renderedComponent), filter.Union[velocity]())
+}
+
+func (suite *ValidateTestSuite) TestValidate_RandomWorkload() {
+	entityIds := make([]uint, 0, numEntities)
+	for range 50 {
+		for range rand.IntN(200) {
+			entityIds = append(entityIds, sandbox.LinkEntity(suite.sandbox))
+		}
+		for _, entityId := range entityIds {
+			switch rand.IntN(6) {
+			case 0:
+				suite.positionLinker.Link(entityId)
+			case 1:
+				suite.positionLinker.Unlink(entityId)
+			case 2:
+				suite.velocityLinker.Link(entityId)
+			case 3:
+				suite.renderedLinker.Link(entityId)
+			case 4:
+				suite.renderedLinker.Unlink(entityId)
+			case 5:
+				sandbox.UnlinkEntity(suite.sandbox, entityId)
+			}
+		}
+		assert.NoError(suite.T(), sandbox.Validate(suite.sandbox))
+
+		// Update panics if the invariants don't hold (options.Validate)
+		sandbox.Update(suite.sandbox)
+		assert.NoError(suite.T(), sandbox.Validate(suite.sandbox))
+
+		linked := entityIds[:0]
+		for _, entityId := range entityIds {
+			if sandbox.IsEntityLinked(suite.sandbox, entityId) {
+				linked = append(linked, entityId)
+			}
+		}
+		entityIds = linked
+	}
+}